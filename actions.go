@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// actionsCommands maps comment severities to the workflow command that
+// annotates with the matching level
+var actionsCommands = map[string]string{
+	severityBlocking: "error",
+	severityNormal:   "warning",
+	severityNit:      "notice",
+}
+
+// writeActionsAnnotations prints a workflow command per unresolved comment
+// and failing check, so running inside a GitHub Actions workflow annotates
+// the PR's Files Changed view. Line comments are placed on their line;
+// general comments and checks annotate the run as a whole.
+func writeActionsAnnotations(w io.Writer, feedback *PRFeedback) {
+	for _, comments := range [][]ReviewComment{feedback.GeneralIssues, feedback.Comments} {
+		for _, comment := range comments {
+			if comment.State == "resolved" {
+				continue
+			}
+			command := actionsCommands[comment.Severity]
+			if command == "" {
+				command = "warning"
+			}
+			properties := []string{"title=" + escapeActionsProperty("Review comment from @"+comment.Author)}
+			if comment.Path != "" {
+				properties = append(properties, "file="+escapeActionsProperty(comment.Path))
+				if line := commentLine(comment); line > 0 {
+					if comment.StartLine != nil && *comment.StartLine > 0 && *comment.StartLine < line {
+						properties = append(properties, fmt.Sprintf("line=%d,endLine=%d", *comment.StartLine, line))
+					} else {
+						properties = append(properties, fmt.Sprintf("line=%d", line))
+					}
+				}
+			}
+			fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(properties, ","), escapeActionsData(comment.Body))
+		}
+	}
+
+	for _, check := range feedback.StatusChecks {
+		message := fmt.Sprintf("%s: %s", check.Name, strings.ToLower(check.Conclusion))
+		if check.DetailsURL != "" {
+			message += " " + check.DetailsURL
+		}
+		fmt.Fprintf(w, "::error title=%s::%s\n", escapeActionsProperty("Failing check"), escapeActionsData(message))
+	}
+}
+
+// escapeActionsData escapes a workflow command's message
+func escapeActionsData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeActionsProperty escapes a workflow command property value, which
+// also can't contain the separators between properties
+func escapeActionsProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
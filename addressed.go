@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// discussionRef finds review comments referenced in commit messages, either
+// by permalink or as "addresses #discussion_r123"
+var discussionRef = regexp.MustCompile(`#discussion_r(\d+)`)
+
+// markAddressed sets AddressedIn on threads referenced by a commit message in
+// the PR, connecting fixes to the feedback they address. A reference to any
+// comment in a thread counts for the whole thread.
+func markAddressed(client *api.RESTClient, feedback *PRFeedback) {
+	if len(feedback.Comments) == 0 {
+		return
+	}
+	type commit struct {
+		SHA    string `json:"sha"`
+		Commit struct {
+			Message string `json:"message"`
+		} `json:"commit"`
+	}
+	commits, err := getAllPages[commit](client, fmt.Sprintf("repos/%s/pulls/%d/commits", feedback.Repo, feedback.PRNumber))
+	if err != nil {
+		if !budgetSkipped(err) {
+			warnf("failed to fetch commits: %v", err)
+		}
+		return
+	}
+
+	// Later commits win, as the latest fix is the one worth looking at
+	addressedBy := make(map[int]string)
+	for _, commit := range commits {
+		for _, match := range discussionRef.FindAllStringSubmatch(commit.Commit.Message, -1) {
+			if id, err := strconv.Atoi(match[1]); err == nil {
+				addressedBy[id] = commit.SHA
+			}
+		}
+	}
+	if len(addressedBy) == 0 {
+		return
+	}
+
+	for i, comment := range feedback.Comments {
+		sha := addressedBy[comment.ID]
+		for _, reply := range comment.Replies {
+			if by, ok := addressedBy[reply.ID]; ok {
+				sha = by
+			}
+		}
+		feedback.Comments[i].AddressedIn = sha
+	}
+}
+
+// splitAddressed separates the threads addressed by a commit, which are shown
+// collapsed unless include is set by --include-addressed
+func splitAddressed(comments []ReviewComment, include bool) (open, addressed []ReviewComment) {
+	if include {
+		return comments, nil
+	}
+	for _, comment := range comments {
+		if comment.AddressedIn != "" {
+			addressed = append(addressed, comment)
+		} else {
+			open = append(open, comment)
+		}
+	}
+	return open, addressed
+}
+
+// printAddressedSummary lists threads addressed by a commit in one line each
+func printAddressedSummary(comments []ReviewComment, separator string) {
+	fmt.Println("\n" + separator + "\n")
+	fmt.Printf("%s%d thread(s) addressed by commits%s %s(--include-addressed to expand)%s\n", colorBold, len(comments), colorReset, colorGray, colorReset)
+	for _, comment := range comments {
+		location := comment.Path
+		if comment.Line != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+		}
+		fmt.Printf("  %s%s%s %s%s: %s%s addressed-in %s%s%s\n", colorBlue, location, colorReset, colorGray, comment.Author, firstLine(comment.Body), colorReset, colorYellow, shortSHA(comment.AddressedIn), colorReset)
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
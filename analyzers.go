@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+)
+
+// Annotation is a finding an analyzer attached to a comment, rendered as a
+// badge next to the comment
+type Annotation struct {
+	Analyzer string `json:"analyzer"`
+	Badge    string `json:"badge"`
+	Message  string `json:"message,omitempty"`
+}
+
+// AnalyzerConfig declares an analyzer run over every fetched comment. A
+// pattern analyzer badges comments whose body matches Pattern; a command
+// analyzer is given the comments as JSON on stdin and prints annotations.
+type AnalyzerConfig struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern,omitempty"`
+	Badge   string `yaml:"badge,omitempty"`
+	Message string `yaml:"message,omitempty"`
+	Command string `yaml:"command,omitempty"`
+}
+
+// analyzerInput is what command analyzers receive on stdin
+type analyzerInput struct {
+	Repo     string          `json:"repo"`
+	PRNumber int             `json:"pr_number"`
+	Comments []ReviewComment `json:"comments"`
+}
+
+// analyzerOutput is one annotation printed by a command analyzer
+type analyzerOutput struct {
+	CommentID int    `json:"comment_id"`
+	Badge     string `json:"badge"`
+	Message   string `json:"message,omitempty"`
+}
+
+// runAnalyzers annotates the feedback's comments with every analyzer's
+// findings. A failing analyzer is reported and skipped so it can't hide the
+// feedback itself.
+func runAnalyzers(feedback *PRFeedback, analyzers []AnalyzerConfig) {
+	if len(analyzers) == 0 {
+		return
+	}
+
+	byID := make(map[int]*ReviewComment)
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for i := range comments {
+			byID[comments[i].ID] = &comments[i]
+		}
+	}
+
+	for _, analyzer := range analyzers {
+		name := analyzer.Name
+		if name == "" {
+			name = analyzer.Command
+		}
+
+		var findings []analyzerOutput
+		var err error
+		if analyzer.Command != "" {
+			findings, err = runCommandAnalyzer(analyzer.Command, feedback)
+		} else {
+			findings, err = runPatternAnalyzer(analyzer, byID)
+		}
+		if err != nil {
+			warnf("analyzer %s failed: %v", name, err)
+			continue
+		}
+
+		for _, finding := range findings {
+			comment, ok := byID[finding.CommentID]
+			if !ok || finding.Badge == "" {
+				continue
+			}
+			comment.Annotations = append(comment.Annotations, Annotation{
+				Analyzer: name,
+				Badge:    finding.Badge,
+				Message:  finding.Message,
+			})
+		}
+	}
+}
+
+func runPatternAnalyzer(analyzer AnalyzerConfig, byID map[int]*ReviewComment) ([]analyzerOutput, error) {
+	if analyzer.Pattern == "" {
+		return nil, fmt.Errorf("needs a pattern or a command")
+	}
+	pattern, err := regexp.Compile(analyzer.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	badge := analyzer.Badge
+	if badge == "" {
+		badge = analyzer.Name
+	}
+
+	var findings []analyzerOutput
+	for id, comment := range byID {
+		if pattern.MatchString(comment.Body) {
+			findings = append(findings, analyzerOutput{CommentID: id, Badge: badge, Message: analyzer.Message})
+		}
+	}
+	return findings, nil
+}
+
+func runCommandAnalyzer(command string, feedback *PRFeedback) ([]analyzerOutput, error) {
+	input := analyzerInput{
+		Repo:     feedback.Repo,
+		PRNumber: feedback.PRNumber,
+		Comments: append(append([]ReviewComment{}, feedback.Comments...), feedback.GeneralIssues...),
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	var findings []analyzerOutput
+	if err := json.Unmarshal(output, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse output: %w", err)
+	}
+	return findings, nil
+}
+
+// configuredAnalyzers returns the analyzers from the config and --analyzer.
+// Commands from a config fetched from the remote repository are skipped:
+// running them would let anyone with push access execute code locally.
+func configuredAnalyzers(config *Config, commands []string) []AnalyzerConfig {
+	var analyzers []AnalyzerConfig
+	for _, analyzer := range config.Analyzers {
+		if analyzer.Command != "" && !config.local {
+			warnf("skipping analyzer %s; commands only run from a local %s", analyzer.Name, configPath)
+			continue
+		}
+		analyzers = append(analyzers, analyzer)
+	}
+	for _, command := range commands {
+		analyzers = append(analyzers, AnalyzerConfig{Command: command})
+	}
+	return analyzers
+}
+
+// formatBadges renders a comment's annotations as inline badges
+func formatBadges(annotations []Annotation) string {
+	var badges string
+	for _, annotation := range annotations {
+		badges += fmt.Sprintf(" %s[%s]%s", colorPurple, annotation.Badge, colorReset)
+	}
+	return badges
+}
+
+// printAnnotations prints the messages analyzers attached to a comment
+func printAnnotations(annotations []Annotation, width int) {
+	if width > 0 {
+		width -= 4
+	}
+	for _, annotation := range annotations {
+		if annotation.Message == "" {
+			continue
+		}
+		for _, line := range wrapBody(annotation.Badge+": "+annotation.Message, width) {
+			fmt.Printf("  %s▸ %s%s\n", colorGray, line, colorReset)
+		}
+	}
+}
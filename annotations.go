@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// CheckAnnotation is a file and line a check run flagged, such as a lint
+// error or failing test
+type CheckAnnotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Level     string `json:"annotation_level"`
+	Title     string `json:"title,omitempty"`
+	Message   string `json:"message"`
+	// Check is the name of the check run that reported it
+	Check string `json:"check,omitempty"`
+	// Owners are the CODEOWNERS of the file, when they aren't you
+	Owners []string `json:"owners,omitempty"`
+}
+
+// checkRunID finds the check run behind a check's details URL: Actions jobs
+// link to <repo>/actions/runs/<run>/job/<id>, other apps' checks on GitHub
+// to <repo>/runs/<id>. Checks linking elsewhere can't be looked up.
+func checkRunID(repo, detailsURL string) string {
+	if strings.Contains(detailsURL, "/actions/runs/") {
+		_, job, ok := strings.Cut(detailsURL, "/job/")
+		if !ok {
+			return ""
+		}
+		job, _, _ = strings.Cut(job, "?")
+		return job
+	}
+	if _, id, ok := strings.Cut(detailsURL, "/"+repo+"/runs/"); ok {
+		id, _, _ = strings.Cut(id, "?")
+		id, _, _ = strings.Cut(id, "/")
+		return id
+	}
+	return ""
+}
+
+// attachCheckAnnotations fetches the failure and warning annotations of the
+// PR's failing check runs. Notices are left out as they're rarely about the
+// change itself.
+func attachCheckAnnotations(client *api.RESTClient, feedback *PRFeedback) {
+	for i, check := range feedback.StatusChecks {
+		id := checkRunID(feedback.Repo, check.DetailsURL)
+		if id == "" {
+			continue
+		}
+		endpoint := fmt.Sprintf("repos/%s/check-runs/%s/annotations", feedback.Repo, id)
+		annotations, err := getAllPages[CheckAnnotation](client, endpoint)
+		if err != nil {
+			if !budgetSkipped(err) {
+				warnf("failed to fetch annotations for %s: %v", check.Name, err)
+			}
+			continue
+		}
+		for _, annotation := range annotations {
+			if annotation.Level == "notice" {
+				continue
+			}
+			annotation.Check = check.Name
+			feedback.StatusChecks[i].Annotations = append(feedback.StatusChecks[i].Annotations, annotation)
+		}
+	}
+}
+
+// annotationsByPath groups the checks' annotations by the file they're on,
+// in line order, for printing under that file's review comments
+func annotationsByPath(checks []StatusCheck) map[string][]CheckAnnotation {
+	byPath := make(map[string][]CheckAnnotation)
+	for _, check := range checks {
+		for _, annotation := range check.Annotations {
+			byPath[annotation.Path] = append(byPath[annotation.Path], annotation)
+		}
+	}
+	for _, annotations := range byPath {
+		sort.SliceStable(annotations, func(i, j int) bool {
+			return annotations[i].StartLine < annotations[j].StartLine
+		})
+	}
+	return byPath
+}
+
+// printFileAnnotations prints a file's check annotations, after its review
+// comments when it has any
+func printFileAnnotations(annotations []CheckAnnotation, width int) {
+	for _, annotation := range annotations {
+		symbol, color := "✗", colorRed
+		if annotation.Level == "warning" {
+			symbol, color = "!", colorYellow
+		}
+		lines := fmt.Sprintf("line %d", annotation.StartLine)
+		if annotation.EndLine > annotation.StartLine {
+			lines = fmt.Sprintf("lines %d-%d", annotation.StartLine, annotation.EndLine)
+		}
+		fmt.Printf("\n%s%s%s %s%s on %s%s %s(%s, %s)%s\n", color, symbol, colorReset, colorBlue, annotation.Path, lines, colorReset, colorGray, annotation.Check, annotation.Level, colorReset)
+		if annotation.Title != "" {
+			fmt.Printf("%s%s%s\n", colorBold, annotation.Title, colorReset)
+		}
+		printBody(annotation.Message, width)
+	}
+}
+
+// printRemainingAnnotations prints the annotations on files nobody has
+// commented on, a file at a time in path order
+func printRemainingAnnotations(byPath map[string][]CheckAnnotation, width int, separator string, afterComments bool) {
+	paths := make([]string, 0, len(byPath))
+	for path := range byPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for i, path := range paths {
+		if afterComments || i > 0 {
+			fmt.Println("\n" + separator)
+		}
+		printFileAnnotations(byPath[path], width)
+	}
+}
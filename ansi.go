@@ -0,0 +1,28 @@
+package main
+
+import "github.com/cli/go-gh/v2/pkg/term"
+
+// ansiEnabled is false once disableColors has run, and gates other escape
+// sequences such as hyperlinks and the TUI's screen control
+var ansiEnabled = true
+
+// setupColors turns off escape codes when NO_COLOR or CLICOLOR=0 is set, or
+// when the console can't interpret them (cmd.exe on older Windows versions)
+func setupColors() {
+	if term.IsColorForced() {
+		enableVirtualTerminal()
+		return
+	}
+	if term.IsColorDisabled() || !enableVirtualTerminal() {
+		disableColors()
+	}
+}
+
+func disableColors() {
+	ansiEnabled = false
+	colorReset, colorRed, colorGreen, colorYellow, colorBlue = "", "", "", "", ""
+	colorPurple, colorCyan, colorGray, colorBold, colorDim = "", "", "", "", ""
+	for i := range authorPalette {
+		authorPalette[i] = ""
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+// enableVirtualTerminal is a no-op outside Windows, where terminals
+// interpret ANSI escape codes natively
+func enableVirtualTerminal() bool {
+	return true
+}
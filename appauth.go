@@ -0,0 +1,261 @@
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// tokenRefreshMargin is how long before expiry an installation token is
+// replaced. Tokens last an hour.
+const tokenRefreshMargin = 5 * time.Minute
+
+// appAuth authenticates as a GitHub App installation instead of a user, so
+// long-running modes can work across an organization without a personal
+// token. It's configured with GITHUB_APP_ID, GITHUB_APP_PRIVATE_KEY (the PEM
+// or a path to it) and optionally GITHUB_APP_INSTALLATION_ID.
+type appAuth struct {
+	appID          string
+	key            *rsa.PrivateKey
+	installationID int64
+	host           string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+var (
+	appAuthOnce     sync.Once
+	appAuthInstance *appAuth
+)
+
+// currentAppAuth returns the GitHub App configured in the environment, or nil
+// when running with the user's gh credentials
+func currentAppAuth() *appAuth {
+	appAuthOnce.Do(func() {
+		appID := os.Getenv("GITHUB_APP_ID")
+		if appID == "" {
+			return
+		}
+		app, err := newAppAuth(appID, os.Getenv("GITHUB_APP_PRIVATE_KEY"), os.Getenv("GITHUB_APP_INSTALLATION_ID"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error configuring GitHub App authentication: %v\n", err)
+			os.Exit(1)
+		}
+		appAuthInstance = app
+	})
+	return appAuthInstance
+}
+
+func newAppAuth(appID, privateKey, installationID string) (*appAuth, error) {
+	if privateKey == "" {
+		return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY is required with GITHUB_APP_ID")
+	}
+	data := []byte(privateKey)
+	if !strings.Contains(privateKey, "-----BEGIN") {
+		var err error
+		if data, err = os.ReadFile(privateKey); err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+	}
+	key, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _ := auth.DefaultHost()
+	app := &appAuth{appID: appID, key: key, host: host}
+	if installationID != "" {
+		if app.installationID, err = strconv.ParseInt(installationID, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_APP_INSTALLATION_ID %q", installationID)
+		}
+	} else if app.installationID, err = app.findInstallation(); err != nil {
+		return nil, err
+	}
+
+	if _, err := app.Token(); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// apiURL is the REST API root for the host, e.g. https://api.github.com/
+func (a *appAuth) apiURL() string {
+	if a.host == "" || a.host == "github.com" {
+		return "https://api.github.com/"
+	}
+	return "https://" + a.host + "/api/v3/"
+}
+
+// jwt signs the short-lived token the app authenticates as itself with
+func (a *appAuth) jwt() (string, error) {
+	now := time.Now()
+	encode := func(v any) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	// Backdated to allow for clock drift, as GitHub recommends
+	claims := map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.appID,
+	}
+	unsigned := encode(map[string]string{"alg": "RS256", "typ": "JWT"}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app token: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// appRequest calls the API authenticated as the app itself
+func (a *appAuth) appRequest(method, path string, response any) error {
+	jwt, err := a.jwt()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, a.apiURL()+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Transport: apiTransport(http.DefaultTransport)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var message struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &message)
+		return fmt.Errorf("%s %s: HTTP %d: %s", method, path, resp.StatusCode, message.Message)
+	}
+	return json.Unmarshal(body, response)
+}
+
+// findInstallation picks the app's installation when it only has one
+func (a *appAuth) findInstallation() (int64, error) {
+	var installations []struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+	if err := a.appRequest("GET", "app/installations", &installations); err != nil {
+		return 0, fmt.Errorf("failed to list app installations: %w", err)
+	}
+	switch len(installations) {
+	case 0:
+		return 0, fmt.Errorf("the GitHub App isn't installed anywhere")
+	case 1:
+		return installations[0].ID, nil
+	}
+	var accounts []string
+	for _, installation := range installations {
+		accounts = append(accounts, fmt.Sprintf("%s (%d)", installation.Account.Login, installation.ID))
+	}
+	return 0, fmt.Errorf("the GitHub App has several installations, set GITHUB_APP_INSTALLATION_ID to one of: %s", strings.Join(accounts, ", "))
+}
+
+// Token returns a valid installation token, creating a new one when the
+// current token is close to expiring
+func (a *appAuth) Token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && time.Until(a.expires) > tokenRefreshMargin {
+		return a.token, nil
+	}
+
+	var response struct {
+		Token     string `json:"token"`
+		ExpiresAt string `json:"expires_at"`
+	}
+	path := fmt.Sprintf("app/installations/%d/access_tokens", a.installationID)
+	if err := a.appRequest("POST", path, &response); err != nil {
+		return "", fmt.Errorf("failed to create installation token: %w", err)
+	}
+	expires, err := parseTime(response.ExpiresAt)
+	if err != nil {
+		expires = time.Now().Add(time.Hour)
+	}
+	a.token, a.expires = response.Token, expires
+
+	// gh subprocesses (PR detection, checks) authenticate with the same token
+	os.Setenv("GH_TOKEN", a.token)
+	return a.token, nil
+}
+
+// clientOptions are the go-gh options for clients authenticating as the app
+func (a *appAuth) clientOptions() api.ClientOptions {
+	token, _ := a.Token()
+	return api.ClientOptions{
+		Host:      a.host,
+		AuthToken: token,
+		Transport: &appTransport{app: a, base: http.DefaultTransport},
+	}
+}
+
+// appTransport replaces the Authorization header go-gh sets with a current
+// installation token, so long-lived clients survive token expiry
+type appTransport struct {
+	app  *appAuth
+	base http.RoundTripper
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		return t.base.RoundTrip(req)
+	}
+	token, err := t.app.Token()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Artifact is a file uploaded by a workflow run, such as a test report
+type Artifact struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Size    int64  `json:"size_in_bytes"`
+	Expired bool   `json:"expired,omitempty"`
+}
+
+// attachArtifacts lists the uploaded artifacts of every failing Actions run
+func attachArtifacts(client *api.RESTClient, feedback *PRFeedback) {
+	artifacts := make(map[string][]Artifact)
+	for i, check := range feedback.StatusChecks {
+		if check.RunID == "" {
+			continue
+		}
+		list, ok := artifacts[check.RunID]
+		if !ok {
+			var err error
+			list, err = fetchRunArtifacts(client, feedback.Repo, check.RunID)
+			if err != nil && !budgetSkipped(err) {
+				warnf("failed to list artifacts for run %s: %v", check.RunID, err)
+			}
+			artifacts[check.RunID] = list
+		}
+		feedback.StatusChecks[i].Artifacts = list
+	}
+}
+
+func fetchRunArtifacts(client *api.RESTClient, repo, runID string) ([]Artifact, error) {
+	var artifacts []Artifact
+	endpoint := fmt.Sprintf("repos/%s/actions/runs/%s/artifacts", repo, runID)
+	err := eachFieldPage(client, endpoint, "artifacts", func(page []Artifact) error {
+		artifacts = append(artifacts, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+// downloadArtifacts fetches the artifacts of every failing run into
+// dir/<run-id>/<artifact-name>, returning how many runs were downloaded.
+func downloadArtifacts(feedback *PRFeedback, dir string) (int, error) {
+	downloaded := make(map[string]bool)
+	for _, check := range feedback.StatusChecks {
+		if check.RunID == "" || downloaded[check.RunID] {
+			continue
+		}
+		available := false
+		for _, artifact := range check.Artifacts {
+			available = available || !artifact.Expired
+		}
+		if !available {
+			continue
+		}
+
+		target := filepath.Join(dir, check.RunID)
+		cmd := exec.Command("gh", "run", "download", check.RunID, "--repo", feedback.Repo, "--dir", target)
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return len(downloaded), fmt.Errorf("failed to download artifacts for run %s: %w", check.RunID, err)
+		}
+		downloaded[check.RunID] = true
+	}
+	return len(downloaded), nil
+}
+
+// formatBytes formats a size in bytes for display, e.g. "1.2 MB"
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// defaultAssignTemplate is the reply posted by `assign`, overridable with
+// assign_template in the repository config
+const defaultAssignTemplate = `{{.Mention}} could you take this one?{{if .AssignedBy}} Assigned to you by @{{.AssignedBy}}.{{end}}`
+
+// assignData is what assign templates are rendered with
+type assignData struct {
+	Mention    string
+	Login      string
+	AssignedBy string
+	Title      string
+	URL        string
+}
+
+// runAssign hands a thread to a collaborator: it replies mentioning them and
+// records the assignment locally so it shows up in the output and JSON.
+func runAssign(args []string) {
+	var commentID int
+	var login, message string
+	var dryRun, undo bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--undo":
+			undo = true
+		case arg == "--message" || arg == "-m":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			message = args[i+1]
+			i++
+		case commentID == 0 && strings.Trim(arg, "0123456789") == "":
+			commentID, _ = strconv.Atoi(arg)
+		case login == "" && !strings.HasPrefix(arg, "-") && strings.Trim(arg, "0123456789") != "":
+			login = strings.TrimPrefix(arg, "@")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if commentID == 0 || (login == "" && !undo) {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback assign <comment-id> <login> [-m template] [--dry-run] [pr-number]\n")
+		fmt.Fprintf(os.Stderr, "       gh pr-feedback assign <comment-id> --undo [pr-number]\n")
+		os.Exit(1)
+	}
+
+	opts := parseArgs(rest)
+	client := resolvePR(opts)
+
+	state, err := loadPRState(opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Unassigning is local only; the earlier reply stays on the thread
+	if undo {
+		delete(state.Assignments, commentID)
+		if err := savePRState(opts.prNumber, state); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving state: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Unassigned comment %d on PR #%d\n", commentID, opts.prNumber)
+		return
+	}
+
+	if message == "" {
+		message = defaultAssignTemplate
+		if config, err := loadConfig(client, opts.repoName); err == nil && config.AssignTemplate != "" {
+			message = config.AssignTemplate
+		}
+	}
+	tmpl, err := parseAssignTemplate(message)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid assign template: %v\n", err)
+		os.Exit(1)
+	}
+
+	feedback, err := fetchPRDetails(client, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR details: %v\n", err)
+		os.Exit(1)
+	}
+	data := assignData{
+		Mention: "@" + login,
+		Login:   login,
+		Title:   feedback.Title,
+		URL:     feedback.URL,
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := client.Get("user", &user); err == nil {
+		data.AssignedBy = user.Login
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid assign template: %v\n", err)
+		os.Exit(1)
+	}
+
+	if dryRun {
+		fmt.Println(body.String())
+		return
+	}
+
+	if err := postComment(client, opts.repoName, opts.prNumber, commentID, body.String()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error posting reply: %v\n", err)
+		os.Exit(1)
+	}
+
+	if state.Assignments == nil {
+		state.Assignments = make(map[int]string)
+	}
+	state.Assignments[commentID] = login
+	if err := savePRState(opts.prNumber, state); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving state: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s✓%s Assigned comment %d on PR #%d to @%s\n", colorGreen, colorReset, commentID, opts.prNumber, login)
+}
+
+func parseAssignTemplate(text string) (*template.Template, error) {
+	return template.New("assign").Parse(text)
+}
+
+// attachAssignments copies recorded assignees onto the threads they belong to
+func attachAssignments(feedback *PRFeedback, state *PRState) {
+	for i := range feedback.Comments {
+		feedback.Comments[i].AssignedTo = state.Assignments[feedback.Comments[i].ID]
+	}
+	for i := range feedback.GeneralIssues {
+		feedback.GeneralIssues[i].AssignedTo = state.Assignments[feedback.GeneralIssues[i].ID]
+	}
+}
+
+// formatAssignee tags a thread handed to a collaborator with `assign`
+func formatAssignee(comment ReviewComment) string {
+	if comment.AssignedTo == "" {
+		return ""
+	}
+	return fmt.Sprintf(" %s• Assigned to @%s%s", colorCyan, comment.AssignedTo, colorReset)
+}
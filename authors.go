@@ -0,0 +1,111 @@
+package main
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// authorPalette are the 256-color codes authors are assigned from. Red and
+// green are left out so authors aren't confused with check results.
+var authorPalette = []string{
+	"\033[38;5;33m",  // blue
+	"\033[38;5;37m",  // teal
+	"\033[38;5;135m", // purple
+	"\033[38;5;172m", // orange
+	"\033[38;5;169m", // pink
+	"\033[38;5;75m",  // light blue
+	"\033[38;5;179m", // sand
+	"\033[38;5;141m", // lavender
+	"\033[38;5;44m",  // cyan
+	"\033[38;5;209m", // salmon
+}
+
+// isBot reports whether a login belongs to a bot account
+func isBot(login string) bool {
+	login = strings.ToLower(login)
+	return strings.HasSuffix(login, "[bot]") || strings.HasSuffix(login, "-bot") || strings.HasSuffix(login, "_bot")
+}
+
+// authorColor returns the color an author is always shown in, derived from
+// a hash of their login so it's stable across runs. Bots are dimmed.
+func authorColor(login string) string {
+	if isBot(login) {
+		return colorDim
+	}
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(login)))
+	return authorPalette[h.Sum32()%uint32(len(authorPalette))]
+}
+
+// filterAuthors keeps the threads and general comments started by one of
+// authors, when any are given, and drops those started by one of excluded,
+// set by --author and --exclude-author
+func filterAuthors(feedback *PRFeedback, authors, excluded []string) {
+	if len(authors) == 0 && len(excluded) == 0 {
+		return
+	}
+	keep := func(comments []ReviewComment) []ReviewComment {
+		kept := comments[:0]
+		for _, comment := range comments {
+			login := strings.TrimPrefix(comment.Author, "@")
+			if len(authors) > 0 && !containsFold(authors, login) {
+				continue
+			}
+			if containsFold(excluded, login) {
+				continue
+			}
+			kept = append(kept, comment)
+		}
+		return kept
+	}
+	feedback.Comments = keep(feedback.Comments)
+	feedback.GeneralIssues = keep(feedback.GeneralIssues)
+}
+
+// commentIsBot reports whether a comment was left by a bot, going by the
+// account type where the API reports it and the login otherwise
+func commentIsBot(comment ReviewComment) bool {
+	return comment.AuthorIsBot || isBot(comment.Author)
+}
+
+// filterBots drops the threads and general comments started by bots for
+// --no-bots ("exclude"), or keeps only those for --bots-only ("only")
+func filterBots(feedback *PRFeedback, mode string) {
+	if mode == "" {
+		return
+	}
+	keep := func(comments []ReviewComment) []ReviewComment {
+		kept := comments[:0]
+		for _, comment := range comments {
+			if commentIsBot(comment) == (mode == "only") {
+				kept = append(kept, comment)
+			}
+		}
+		return kept
+	}
+	feedback.Comments = keep(feedback.Comments)
+	feedback.GeneralIssues = keep(feedback.GeneralIssues)
+}
+
+// authorAssociations are the relationships GitHub reports between a comment's
+// author and the repository
+var authorAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR", "CONTRIBUTOR", "FIRST_TIME_CONTRIBUTOR", "FIRST_TIMER", "MANNEQUIN", "NONE"}
+
+// filterAssociations keeps only the threads and general comments started by
+// authors with one of associations, e.g. MEMBER, set by --association
+func filterAssociations(feedback *PRFeedback, associations []string) {
+	if len(associations) == 0 {
+		return
+	}
+	keep := func(comments []ReviewComment) []ReviewComment {
+		kept := comments[:0]
+		for _, comment := range comments {
+			if containsFold(associations, comment.AuthorAssoc) {
+				kept = append(kept, comment)
+			}
+		}
+		return kept
+	}
+	feedback.Comments = keep(feedback.Comments)
+	feedback.GeneralIssues = keep(feedback.GeneralIssues)
+}
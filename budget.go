@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// errBudgetExhausted is returned for requests made after --max-requests
+// have been used up
+var errBudgetExhausted = errors.New("API request budget exhausted")
+
+// budgetPriority is the order skipped work is reported in. Fetches already
+// run in this order, so comments are the last thing a small budget loses.
+var budgetPriority = []string{"comments", "checks"}
+
+// requestBudget caps the API requests made by one invocation
+type requestBudget struct {
+	mu      sync.Mutex
+	max     int
+	used    int
+	skipped map[string]int
+}
+
+// budget is the request budget for this run; a max of 0 means unlimited
+var budget = &requestBudget{skipped: make(map[string]int)}
+
+// take spends one request on category, failing once the budget is used up
+func (b *requestBudget) take(category string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.max > 0 && b.used >= b.max {
+		b.skipped[category]++
+		return fmt.Errorf("%w (--max-requests %d)", errBudgetExhausted, b.max)
+	}
+	b.used++
+	return nil
+}
+
+// report prints what was skipped for lack of budget, if anything
+func (b *requestBudget) report() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.skipped) == 0 {
+		return
+	}
+
+	rank := func(category string) int {
+		for i, c := range budgetPriority {
+			if c == category {
+				return i
+			}
+		}
+		return len(budgetPriority)
+	}
+	var categories []string
+	for category := range b.skipped {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if rank(categories[i]) != rank(categories[j]) {
+			return rank(categories[i]) < rank(categories[j])
+		}
+		return categories[i] < categories[j]
+	})
+
+	var parts []string
+	for _, category := range categories {
+		parts = append(parts, fmt.Sprintf("%d for %s", b.skipped[category], category))
+	}
+	fmt.Fprintf(os.Stderr, "%sUsed all %d API requests allowed by --max-requests; skipped requests: %s%s\n",
+		colorYellow, b.max, strings.Join(parts, ", "), colorReset)
+}
+
+// budgetSkipped reports whether err is a request refused by the budget,
+// whose warnings are left to the summary printed by report
+func budgetSkipped(err error) bool {
+	return errors.Is(err, errBudgetExhausted)
+}
+
+// requestCategory groups API paths into what they're fetched for
+func requestCategory(path string) string {
+	switch {
+	case strings.Contains(path, "/requested_reviewers"), strings.Contains(path, "/timeline"):
+		return "review requests"
+	case strings.Contains(path, "/artifacts"):
+		return "artifacts"
+	case strings.Contains(path, "/contents/"):
+		return "config"
+	case strings.Contains(path, "/check-runs"), strings.Contains(path, "/status"):
+		return "checks"
+	case strings.HasSuffix(path, "/graphql"), strings.Contains(path, "/comments"),
+		strings.Contains(path, "/reviews"), strings.Contains(path, "/pulls"):
+		return "comments"
+	default:
+		return "other"
+	}
+}
+
+// budgetTransport charges every API request to the budget
+type budgetTransport struct {
+	base http.RoundTripper
+}
+
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := budget.take(requestCategory(req.URL.Path)); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
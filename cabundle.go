@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// useCABundle trusts the certificates in path on top of the system roots,
+// for corporate proxies that re-sign TLS traffic. HTTP(S)_PROXY is already
+// honored by the default transport, so the bundle is added to it and every
+// client built on it picks both up.
+func useCABundle(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("no PEM certificates found in %s", path)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	http.DefaultTransport = transport
+
+	// gh is also written in Go. SSL_CERT_FILE would replace the system
+	// roots for it, but the certificates in the SSL_CERT_DIR directories are
+	// loaded on top of them, so the bundle is added there in a directory of
+	// its own.
+	dir, err := caBundleDir(data)
+	if err != nil {
+		return err
+	}
+	if existing := os.Getenv("SSL_CERT_DIR"); existing != "" {
+		dir += string(os.PathListSeparator) + existing
+	}
+	os.Setenv("SSL_CERT_DIR", dir)
+	return nil
+}
+
+// caBundleDir returns a directory holding only the bundle, named after its
+// contents so it's written once and concurrent runs don't clobber it
+func caBundleDir(data []byte) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find a directory for the CA bundle: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	dir := filepath.Join(cache, "gh-pr-feedback", "ca", hex.EncodeToString(sum[:8]))
+	file := filepath.Join(dir, "bundle.pem")
+	if _, err := os.Stat(file); err == nil {
+		return dir, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to write the CA bundle for gh: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write the CA bundle for gh: %w", err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+)
+
+// checkProviderLabels are the tags shown for each CI system a check can
+// come from
+var checkProviderLabels = map[string]string{
+	"github-actions":      "Actions",
+	"buildkite":           "Buildkite",
+	"circleci":            "CircleCI",
+	"jenkins":             "Jenkins",
+	"gitlab-ci":           "GitLab CI",
+	"bitbucket-pipelines": "Bitbucket Pipelines",
+}
+
+// classifyChecks normalizes each check's details URL and sets the CI system
+// it came from, so failures can be routed to the right runbooks
+func classifyChecks(checks []StatusCheck) {
+	for i := range checks {
+		checks[i].DetailsURL = normalizeDetailsURL(checks[i].DetailsURL)
+		checks[i].Provider = checkProvider(checks[i].DetailsURL)
+	}
+}
+
+// normalizeDetailsURL drops the parts of a details URL that only say how it
+// was reached, so the same job always has the same URL
+func normalizeDetailsURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	query := u.Query()
+	for key := range query {
+		if key == "check_suite_focus" || key == "pr" || strings.HasPrefix(key, "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	// Jenkins links to the build through a redirect to the configured UI
+	u.Path = strings.TrimSuffix(u.Path, "display/redirect")
+	return u.String()
+}
+
+// checkProvider identifies the CI system behind a details URL, or "custom"
+// for anything else
+func checkProvider(detailsURL string) string {
+	u, err := url.Parse(detailsURL)
+	if err != nil || u.Host == "" {
+		return "custom"
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case strings.Contains(u.Path, "/actions/runs/"):
+		return "github-actions"
+	case host == "buildkite.com" || strings.HasSuffix(host, ".buildkite.com"):
+		return "buildkite"
+	case host == "circleci.com" || strings.HasSuffix(host, ".circleci.com"):
+		return "circleci"
+	case strings.Contains(u.Path, "/-/jobs/") || strings.Contains(u.Path, "/-/pipelines/"):
+		return "gitlab-ci"
+	case host == "bitbucket.org" && strings.Contains(u.Path, "/pipelines/"):
+		return "bitbucket-pipelines"
+	case strings.Contains(host, "jenkins") || strings.Contains(u.Path, "/job/"):
+		return "jenkins"
+	}
+	return "custom"
+}
+
+// formatCheckProvider renders a check's CI system as a tag, naming custom
+// systems by their host
+func formatCheckProvider(check StatusCheck) string {
+	label, ok := checkProviderLabels[check.Provider]
+	if !ok {
+		u, err := url.Parse(check.DetailsURL)
+		if err != nil || u.Host == "" {
+			return ""
+		}
+		label = u.Hostname()
+	}
+	return " " + colorGray + "[" + label + "]" + colorReset
+}
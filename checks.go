@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// followInterval is how often `checks --follow` polls the job
+const followInterval = 5 * time.Second
+
+// logTimestamp is the timestamp GitHub prefixes every log line with
+var logTimestamp = regexp.MustCompile(`^\d{4}-\d\d-\d\dT[\d:.]+Z `)
+
+// prCheck is a check from the PR's status rollup, whatever its state
+type prCheck struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	Conclusion   string `json:"conclusion"`
+	DetailsURL   string `json:"detailsUrl"`
+	WorkflowName string `json:"workflowName"`
+	StartedAt    string `json:"startedAt"`
+	CompletedAt  string `json:"completedAt"`
+}
+
+// actionsJob is an Actions job with the progress of its steps
+type actionsJob struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+	Steps      []struct {
+		Number      int    `json:"number"`
+		Name        string `json:"name"`
+		Status      string `json:"status"`
+		Conclusion  string `json:"conclusion"`
+		StartedAt   string `json:"started_at"`
+		CompletedAt string `json:"completed_at"`
+	} `json:"steps"`
+}
+
+// runChecks lists the PR's checks, or with --follow watches one of them,
+// picking up reruns, and prints its log once it finishes.
+func runChecks(args []string) {
+	var follow string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--follow" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback checks [--follow <check-name>] [pr-number]\n")
+				os.Exit(1)
+			}
+			follow = args[i+1]
+			i++
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+
+	opts := parseArgs(rest)
+	client := resolvePR(opts)
+
+	checks, err := fetchPRChecks(opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if follow == "" {
+		for _, check := range checks {
+			state := strings.ToLower(check.Status)
+			if check.Conclusion != "" {
+				state = strings.ToLower(check.Conclusion)
+			}
+			fmt.Printf("%-16s %s\n", state, check.Name)
+		}
+		return
+	}
+
+	if err := followCheck(client, opts, follow); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func fetchPRChecks(repo string, prNumber int) ([]prCheck, error) {
+	output, err := ghOutput("pr", "view", strconv.Itoa(prNumber), "--repo", repo, "--json", "statusCheckRollup")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status checks: %w", err)
+	}
+	var result struct {
+		StatusCheckRollup []prCheck `json:"statusCheckRollup"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse status checks: %w", err)
+	}
+	return result.StatusCheckRollup, nil
+}
+
+// findJobID finds the Actions job behind the named check on the PR's latest
+// commit, which changes when the check is rerun
+func findJobID(repo string, prNumber int, name string) (string, error) {
+	checks, err := fetchPRChecks(repo, prNumber)
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for _, check := range checks {
+		if !strings.EqualFold(check.Name, name) {
+			names = append(names, check.Name)
+			continue
+		}
+		_, job, ok := strings.Cut(check.DetailsURL, "/job/")
+		if !ok || extractRunID(check.DetailsURL) == "" {
+			return "", fmt.Errorf("check %q isn't a GitHub Actions job", check.Name)
+		}
+		job, _, _ = strings.Cut(job, "?")
+		return job, nil
+	}
+	return "", fmt.Errorf("no check named %q on PR #%d (checks: %s)", name, prNumber, strings.Join(names, ", "))
+}
+
+// findWorkflowRunID finds the Actions run of the named workflow on the PR's
+// latest commit
+func findWorkflowRunID(repo string, prNumber int, workflow string) (string, error) {
+	checks, err := fetchPRChecks(repo, prNumber)
+	if err != nil {
+		return "", err
+	}
+	var workflows []string
+	for _, check := range checks {
+		if check.WorkflowName == "" {
+			continue
+		}
+		if strings.EqualFold(check.WorkflowName, workflow) {
+			if runID := extractRunID(check.DetailsURL); runID != "" {
+				return runID, nil
+			}
+		}
+		if !containsFold(workflows, check.WorkflowName) {
+			workflows = append(workflows, check.WorkflowName)
+		}
+	}
+	return "", fmt.Errorf("no check or workflow named %q on PR #%d (workflows: %s)", workflow, prNumber, strings.Join(workflows, ", "))
+}
+
+// followCheck prints the job's steps as they start and finish, then its log.
+// The log can't be streamed, as GitHub only serves it once the job has
+// finished. It returns an error if the job fails.
+func followCheck(client *api.RESTClient, opts *options, name string) error {
+	jobID := ""
+	var printed map[int]string
+	for {
+		// Look the job up again each time so a rerun is followed
+		id, err := findJobID(opts.repoName, opts.prNumber, name)
+		if err != nil {
+			return err
+		}
+		if id != jobID {
+			if jobID != "" {
+				fmt.Printf("\n%sCheck was rerun, following the new job%s\n", colorYellow, colorReset)
+			}
+			jobID = id
+			printed = nil
+		}
+
+		var job actionsJob
+		if err := client.Get(fmt.Sprintf("repos/%s/actions/jobs/%s", opts.repoName, jobID), &job); err != nil {
+			return fmt.Errorf("failed to fetch job: %w", err)
+		}
+		if printed == nil {
+			printed = make(map[int]string)
+			fmt.Printf("%s%s%s %s%s%s\n", colorBold, job.Name, colorReset, colorGray, job.HTMLURL, colorReset)
+			if job.Status != "completed" {
+				fmt.Printf("%sShowing step progress; GitHub only serves the log once the job finishes%s\n", colorGray, colorReset)
+			}
+			fmt.Println()
+		}
+
+		for _, step := range job.Steps {
+			if printed[step.Number] == step.Status {
+				continue
+			}
+			printed[step.Number] = step.Status
+			switch step.Status {
+			case "in_progress":
+				fmt.Printf("%s▸%s %s\n", colorYellow, colorReset, step.Name)
+			case "completed":
+				symbol, color := "✓", colorGreen
+				if step.Conclusion == "failure" {
+					symbol, color = "✗", colorRed
+				} else if step.Conclusion == "skipped" || step.Conclusion == "cancelled" {
+					symbol, color = "-", colorGray
+				}
+				took := ""
+				start, err1 := parseTime(step.StartedAt)
+				end, err2 := parseTime(step.CompletedAt)
+				if err1 == nil && err2 == nil {
+					took = fmt.Sprintf(" %s(took %s)%s", colorGray, formatDuration(end.Sub(start)), colorReset)
+				}
+				fmt.Printf("%s%s%s %s%s\n", color, symbol, colorReset, step.Name, took)
+			}
+		}
+
+		if job.Status == "completed" {
+			// Logs can only be downloaded once the job has finished
+			fmt.Printf("\n%sLog%s\n\n", colorBold, colorReset)
+			if err := printJobLog(client, opts.repoName, jobID); err != nil {
+				warnf("%v", err)
+			}
+			if job.Conclusion != "success" {
+				return fmt.Errorf("%s finished with %s", job.Name, job.Conclusion)
+			}
+			fmt.Printf("\n%s✓%s %s succeeded\n", colorGreen, colorReset, job.Name)
+			return nil
+		}
+		time.Sleep(followInterval)
+	}
+}
+
+func printJobLog(client *api.RESTClient, repo, jobID string) error {
+	response, err := client.Request("GET", fmt.Sprintf("repos/%s/actions/jobs/%s/logs", repo, jobID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to download log: %w", err)
+	}
+	defer response.Body.Close()
+
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fmt.Println(logTimestamp.ReplaceAllString(scanner.Text(), ""))
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log: %w", err)
+	}
+	return nil
+}
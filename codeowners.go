@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// codeownersPaths are where GitHub looks for a CODEOWNERS file, in order
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeownersRule is one line of a CODEOWNERS file
+type codeownersRule struct {
+	pattern string
+	owners  []string
+}
+
+// loadCodeowners reads the repository's CODEOWNERS file, preferring the local
+// checkout like the config. It returns no rules when there isn't one.
+func loadCodeowners(client *api.RESTClient, repo string) ([]codeownersRule, error) {
+	for _, path := range codeownersPaths {
+		data, err := readLocalFile(path)
+		if err == nil && data == nil {
+			data, err = readRemoteFile(client, repo, path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			return parseCodeowners(string(data)), nil
+		}
+	}
+	return nil, nil
+}
+
+func parseCodeowners(text string) []codeownersRule {
+	var rules []codeownersRule
+	for _, line := range strings.Split(text, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, codeownersRule{pattern: fields[0], owners: fields[1:]})
+	}
+	return rules
+}
+
+// codeownersOf returns the owners of path. As on GitHub the last matching
+// rule wins, and a rule without owners leaves the path unowned.
+func codeownersOf(rules []codeownersRule, path string) []string {
+	var owners []string
+	for _, rule := range rules {
+		if codeownersMatch(rule.pattern, path) {
+			owners = rule.owners
+		}
+	}
+	return owners
+}
+
+// codeownersMatch matches path against a CODEOWNERS pattern, which follows
+// .gitignore rules: patterns without a leading or inner slash match at any
+// depth, and patterns naming a directory cover everything under it
+func codeownersMatch(pattern, path string) bool {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !anchored {
+		pattern = "**/" + pattern
+	}
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	return globMatch(pattern, path) || globMatch(pattern+"/**", path)
+}
+
+// attachCheckOwners notes who owns the files a failing check flagged, when
+// that isn't you or one of your teams, so failures you can't fix yourself
+// stand out in a monorepo.
+func attachCheckOwners(client *api.RESTClient, feedback *PRFeedback) {
+	flagged := false
+	for _, check := range feedback.StatusChecks {
+		flagged = flagged || len(check.Annotations) > 0
+	}
+	if !flagged {
+		return
+	}
+	rules, err := loadCodeowners(client, feedback.Repo)
+	if err != nil {
+		if !budgetSkipped(err) {
+			warnf("%v", err)
+		}
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+	mine := fetchOwnIdentities(client)
+
+	for i, check := range feedback.StatusChecks {
+		seen := make(map[string]bool)
+		for j, annotation := range check.Annotations {
+			owners := codeownersOf(rules, annotation.Path)
+			if ownedBy(owners, mine) {
+				continue
+			}
+			check.Annotations[j].Owners = owners
+			for _, owner := range owners {
+				if !seen[strings.ToLower(owner)] {
+					seen[strings.ToLower(owner)] = true
+					feedback.StatusChecks[i].Owners = append(feedback.StatusChecks[i].Owners, owner)
+				}
+			}
+		}
+	}
+}
+
+// fetchOwnIdentities returns the ways CODEOWNERS can name you: @login and
+// @org/team for each of your teams. Listing teams needs the read:org scope,
+// without which only your login is known.
+func fetchOwnIdentities(client *api.RESTClient) map[string]bool {
+	mine := make(map[string]bool)
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := client.Get("user", &user); err == nil {
+		mine["@"+strings.ToLower(user.Login)] = true
+	}
+	type team struct {
+		Slug         string `json:"slug"`
+		Organization struct {
+			Login string `json:"login"`
+		} `json:"organization"`
+	}
+	teams, err := getAllPages[team](client, "user/teams")
+	if err != nil {
+		return mine
+	}
+	for _, team := range teams {
+		mine[strings.ToLower("@"+team.Organization.Login+"/"+team.Slug)] = true
+	}
+	return mine
+}
+
+func ownedBy(owners []string, mine map[string]bool) bool {
+	if len(owners) == 0 {
+		return true
+	}
+	for _, owner := range owners {
+		if mine[strings.ToLower(owner)] {
+			return true
+		}
+	}
+	return false
+}
+
+// routeFailures tells the owners of each failing check's files about it, by
+// mentioning them on the PR or opening an issue. Checks already routed are
+// skipped, so running it again doesn't notify anyone twice.
+func routeFailures(client *api.RESTClient, feedback *PRFeedback, mode string) {
+	for _, check := range feedback.StatusChecks {
+		if len(check.Owners) == 0 {
+			continue
+		}
+		marker := fmt.Sprintf("<!-- pr-feedback:route %s -->", check.Name)
+		body := formatRoutedFailure(feedback, check, marker)
+		owners := strings.Join(check.Owners, ", ")
+
+		switch mode {
+		case "mention":
+			routed, err := routedBefore(client, feedback, marker)
+			if err != nil {
+				warnf("failed to check whether %s was told about %s: %v", owners, check.Name, err)
+				continue
+			}
+			if routed {
+				continue
+			}
+			if err := postComment(client, feedback.Repo, feedback.PRNumber, 0, body); err != nil {
+				warnf("failed to mention %s about %s: %v", owners, check.Name, err)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Mentioned %s about %s on PR #%d\n", owners, check.Name, feedback.PRNumber)
+		case "issue":
+			title := fmt.Sprintf("%s failing on #%d", check.Name, feedback.PRNumber)
+			existing, err := searchPRs(client, fmt.Sprintf("repo:%s is:issue is:open in:title %q", feedback.Repo, title))
+			if err != nil {
+				warnf("failed to look for an issue about %s: %v", check.Name, err)
+				continue
+			}
+			if len(existing) > 0 {
+				continue
+			}
+			payload, err := json.Marshal(map[string]string{"title": title, "body": body})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			var issue struct {
+				HTMLURL string `json:"html_url"`
+			}
+			if err := client.Post(fmt.Sprintf("repos/%s/issues", feedback.Repo), bytes.NewReader(payload), &issue); err != nil {
+				warnf("failed to open an issue about %s: %v", check.Name, err)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Opened %s for %s\n", issue.HTMLURL, owners)
+		}
+	}
+}
+
+// errRouted stops listing the PR's comments once the routing comment is found
+var errRouted = errors.New("already routed")
+
+// routedBefore reports whether a routing comment with marker was already
+// posted on the PR. It reads the comments from GitHub rather than the
+// feedback, where --since, --author and the like may have filtered it out.
+func routedBefore(client *api.RESTClient, feedback *PRFeedback, marker string) (bool, error) {
+	endpoint := fmt.Sprintf("repos/%s/issues/%d/comments", feedback.Repo, feedback.PRNumber)
+	err := eachPage(client, endpoint, func(comments []struct {
+		Body string `json:"body"`
+	}) error {
+		for _, comment := range comments {
+			if strings.Contains(comment.Body, marker) {
+				return errRouted
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errRouted) {
+		return true, nil
+	}
+	return false, err
+}
+
+func formatRoutedFailure(feedback *PRFeedback, check StatusCheck, marker string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: the **%s** check on %s is failing in files you own", strings.Join(check.Owners, " "), check.Name, feedback.URL)
+	if check.DetailsURL != "" {
+		fmt.Fprintf(&b, " ([details](%s))", check.DetailsURL)
+	}
+	b.WriteString(".\n\n")
+	for _, annotation := range check.Annotations {
+		if len(annotation.Owners) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "- `%s` line %d: %s\n", annotation.Path, annotation.StartLine, firstLine(annotation.Message))
+	}
+	b.WriteString("\n" + marker + "\n")
+	return b.String()
+}
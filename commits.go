@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// fetchCommitRange lists the commits in a "<base>..<head>" range, i.e. those
+// reachable from head but not from base, as git rev-list does
+func fetchCommitRange(client *api.RESTClient, repo, spec string) (map[string]bool, error) {
+	base, head, ok := strings.Cut(spec, "..")
+	if !ok || base == "" || head == "" || strings.HasPrefix(head, ".") {
+		return nil, fmt.Errorf("invalid commit range %q, expected <sha1>..<sha2>", spec)
+	}
+
+	type commit struct {
+		SHA string `json:"sha"`
+	}
+	commits := make(map[string]bool)
+	endpoint := fmt.Sprintf("repos/%s/compare/%s...%s", repo, base, head)
+	err := eachFieldPage(client, endpoint, "commits", func(page []commit) error {
+		for _, commit := range page {
+			commits[commit.SHA] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s: %w", spec, err)
+	}
+	return commits, nil
+}
+
+// filterByCommits keeps only the line comments whose thread was started on
+// one of the given commits, returning how many were dropped. Replies follow
+// their thread.
+func filterByCommits(feedback *PRFeedback, commits map[string]bool) int {
+	threadCommit := make(map[int]string)
+	for _, comment := range feedback.Comments {
+		if comment.InReplyTo == nil {
+			threadCommit[comment.ID] = comment.CommitID
+		}
+	}
+
+	var kept []ReviewComment
+	for _, comment := range feedback.Comments {
+		commit := comment.CommitID
+		if comment.InReplyTo != nil {
+			if root, ok := threadCommit[*comment.InReplyTo]; ok {
+				commit = root
+			}
+		}
+		if commits[commit] {
+			kept = append(kept, comment)
+		}
+	}
+	dropped := len(feedback.Comments) - len(kept)
+	feedback.Comments = kept
+	return dropped
+}
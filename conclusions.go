@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkConclusions are the conclusions GitHub reports for check runs and, as
+// states, for commit statuses
+var checkConclusions = []string{
+	"ACTION_REQUIRED", "CANCELLED", "ERROR", "FAILURE", "NEUTRAL",
+	"SKIPPED", "STALE", "STARTUP_FAILURE", "SUCCESS", "TIMED_OUT",
+}
+
+// failingConclusions are the conclusions reported as failing checks,
+// changed with --check-conclusions
+var failingConclusions = map[string]bool{
+	"FAILURE":         true,
+	"ERROR":           true,
+	"CANCELLED":       true,
+	"TIMED_OUT":       true,
+	"ACTION_REQUIRED": true,
+}
+
+func isFailingConclusion(conclusion string) bool {
+	return failingConclusions[strings.ToUpper(conclusion)]
+}
+
+// setFailingConclusions replaces the failing conclusions with a
+// comma-separated list such as "failure,timed_out"
+func setFailingConclusions(list string) error {
+	conclusions := make(map[string]bool)
+	for _, conclusion := range strings.Split(list, ",") {
+		conclusion = strings.ToUpper(strings.TrimSpace(conclusion))
+		if conclusion == "" {
+			continue
+		}
+		known := false
+		for _, c := range checkConclusions {
+			known = known || c == conclusion
+		}
+		if !known {
+			return fmt.Errorf("unknown check conclusion %q (expected one of %s)", strings.ToLower(conclusion), strings.ToLower(strings.Join(checkConclusions, ", ")))
+		}
+		conclusions[conclusion] = true
+	}
+	if len(conclusions) == 0 {
+		return fmt.Errorf("--check-conclusions needs at least one conclusion")
+	}
+	failingConclusions = conclusions
+	return nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// configPath is where repositories keep their gh-pr-feedback policy
+const configPath = ".github/pr-feedback.yml"
+
+// Config is the repository-level configuration read from configPath
+type Config struct {
+	Gate      []GateRule       `yaml:"gate"`
+	Score     ScoreWeights     `yaml:"score"`
+	Analyzers []AnalyzerConfig `yaml:"analyzers"`
+	// Renderers condense bot comments, on top of defaultRenderers
+	Renderers []RendererConfig `yaml:"renderers"`
+	// PingTemplate is the text/template posted by `ping`
+	PingTemplate string `yaml:"ping_template"`
+	// AssignTemplate is the text/template replied by `assign`
+	AssignTemplate string `yaml:"assign_template"`
+	// Followup classifies deferred feedback for `export --by-label`,
+	// replacing defaultFollowupRules
+	Followup []FollowupRule `yaml:"followup"`
+	// Vendored replaces defaultVendoredPaths. Paths marked linguist-generated
+	// or linguist-vendored in .gitattributes are always included.
+	Vendored []string `yaml:"vendored"`
+	// Docs link threads on files under a path to relevant documentation
+	Docs []DocLink `yaml:"docs"`
+	// SummarizeCommand generates the --summarize summary. It only runs
+	// from a local config.
+	SummarizeCommand string `yaml:"summarize_command"`
+
+	// local is set when the config came from the local checkout rather
+	// than the remote repository
+	local bool
+}
+
+// GateRule is a single requirement evaluated by `gh pr-feedback gate`. A
+// rule fails when more than Max matching comments or checks remain.
+type GateRule struct {
+	Name     string        `yaml:"name"`
+	Comments *CommentMatch `yaml:"comments,omitempty"`
+	Checks   *CheckMatch   `yaml:"checks,omitempty"`
+	Max      int           `yaml:"max,omitempty"`
+}
+
+// CommentMatch selects unresolved comments. Empty fields match everything.
+type CommentMatch struct {
+	Authors []string `yaml:"authors,omitempty"`
+	Paths   []string `yaml:"paths,omitempty"`
+	Match   string   `yaml:"match,omitempty"`
+	// Labels match the comment's conventional comment label and decorations,
+	// e.g. "issue (security):", or a bracketed tag such as [security]
+	Labels []string `yaml:"labels,omitempty"`
+}
+
+// CheckMatch selects failing checks by name, workflow or whether branch
+// protection requires them. Empty fields match every failing check.
+type CheckMatch struct {
+	Names     []string `yaml:"names,omitempty"`
+	Workflows []string `yaml:"workflows,omitempty"`
+	Required  *bool    `yaml:"required,omitempty"`
+}
+
+// loadConfig reads the repository config, preferring the local checkout and
+// falling back to the copy on the repository's default branch. A missing
+// config is not an error.
+func loadConfig(client *api.RESTClient, repo string) (*Config, error) {
+	data, err := readLocalFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	local := data != nil
+	if data == nil && client != nil && repo != "" {
+		data, err = readRemoteFile(client, repo, configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Weights left out of the file keep their defaults
+	config := &Config{Score: defaultScoreWeights, local: local}
+	if data == nil {
+		return config, nil
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	return config, nil
+}
+
+// readLocalFile reads a file relative to the root of the local checkout,
+// returning nil when it doesn't exist
+func readLocalFile(path string) ([]byte, error) {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, nil
+	}
+	root := strings.TrimSpace(string(output))
+	data, err := os.ReadFile(filepath.Join(root, path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// readRemoteFile reads a file from the repository's default branch,
+// returning nil when it doesn't exist
+func readRemoteFile(client *api.RESTClient, repo, path string) ([]byte, error) {
+	var content struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	endpoint := fmt.Sprintf("repos/%s/contents/%s", repo, path)
+	if err := client.Get(endpoint, &content); err != nil {
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", path, err)
+	}
+	if content.Encoding != "base64" {
+		return []byte(content.Content), nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// globMatch reports whether path matches a glob pattern. In addition to the
+// usual wildcards, "**" matches any number of directories.
+func globMatch(pattern, path string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" also matches zero directories
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					expr.WriteString("(?:.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	matched, err := regexp.MatchString(expr.String(), path)
+	return err == nil && matched
+}
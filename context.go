@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// RunContext is everything gh pr-feedback resolved about where it's running,
+// as printed by the context command
+type RunContext struct {
+	Directory    string   `json:"directory"`
+	Provider     string   `json:"provider"`
+	Repo         string   `json:"repo,omitempty"`
+	PRNumber     int      `json:"pr_number,omitempty"`
+	Branch       string   `json:"branch,omitempty"`
+	Host         string   `json:"host,omitempty"`
+	TokenSource  string   `json:"token_source,omitempty"`
+	User         string   `json:"user,omitempty"`
+	RateLimit    *Rate    `json:"rate_limit,omitempty"`
+	GraphQLLimit *Rate    `json:"graphql_rate_limit,omitempty"`
+	Backend      string   `json:"backend"`
+	GhPath       string   `json:"gh_path,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// Rate is the remaining API quota
+type Rate struct {
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Reset     string `json:"reset"`
+}
+
+// runContext prints what the default command would look at, without failing
+// on the first thing that can't be resolved.
+func runContext(args []string) {
+	opts := parseArgs(args)
+	provider := selectProvider(opts)
+
+	info := &RunContext{Provider: provider.Name(), Backend: "api"}
+	info.Directory, _ = os.Getwd()
+	info.Branch, _ = currentBranch()
+	if path, err := exec.LookPath("gh"); err == nil {
+		info.GhPath = path
+	}
+	if provider.Name() == "github" {
+		// PR detection and status checks shell out to gh; everything else
+		// goes through the REST and GraphQL APIs
+		info.Backend = "api+gh"
+		if info.GhPath == "" {
+			info.Errors = append(info.Errors, "gh is not on PATH; PR detection and status checks will fail")
+		}
+	}
+
+	info.Repo = opts.repoName
+	if info.Repo == "" {
+		repo, err := provider.CurrentRepo()
+		if err != nil {
+			info.Errors = append(info.Errors, "repository: "+err.Error())
+		}
+		info.Repo = repo
+	}
+	info.PRNumber = opts.prNumber
+	if info.PRNumber == 0 && info.Repo != "" {
+		number, err := provider.CurrentPR(info.Repo)
+		if err != nil {
+			info.Errors = append(info.Errors, "pull request: "+err.Error())
+		}
+		info.PRNumber = number
+	}
+
+	if github, ok := provider.(*githubProvider); ok {
+		host, _ := auth.DefaultHost()
+		info.Host = host
+		if _, source := auth.TokenForHost(host); source != "" {
+			info.TokenSource = source
+		}
+
+		// Installation tokens have no user behind them
+		if app := currentAppAuth(); app != nil {
+			info.TokenSource = fmt.Sprintf("GitHub App %s (installation %d)", app.appID, app.installationID)
+		} else {
+			var user struct {
+				Login string `json:"login"`
+			}
+			if err := github.client.Get("user", &user); err != nil {
+				info.Errors = append(info.Errors, "user: "+err.Error())
+			}
+			info.User = user.Login
+		}
+
+		var limits struct {
+			Resources struct {
+				Core    rateLimit `json:"core"`
+				GraphQL rateLimit `json:"graphql"`
+			} `json:"resources"`
+		}
+		if err := github.client.Get("rate_limit", &limits); err != nil {
+			info.Errors = append(info.Errors, "rate limit: "+err.Error())
+		} else {
+			info.RateLimit = limits.Resources.Core.rate()
+			info.GraphQLLimit = limits.Resources.GraphQL.rate()
+		}
+	}
+
+	if opts.jsonOutput {
+		printJSON(opts, info)
+		return
+	}
+	printContext(info)
+}
+
+type rateLimit struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"`
+}
+
+func (r rateLimit) rate() *Rate {
+	return &Rate{
+		Limit:     r.Limit,
+		Remaining: r.Remaining,
+		Reset:     time.Unix(r.Reset, 0).UTC().Format(time.RFC3339),
+	}
+}
+
+func printContext(info *RunContext) {
+	field := func(name, value string) {
+		if value == "" {
+			value = colorGray + "(unknown)" + colorReset
+		}
+		fmt.Printf("%s%-14s%s %s\n", colorBold, name, colorReset, value)
+	}
+	formatRate := func(rate *Rate) string {
+		if rate == nil {
+			return ""
+		}
+		reset, _ := parseTime(rate.Reset)
+		return fmt.Sprintf("%d/%d remaining, resets in %s", rate.Remaining, rate.Limit, formatDuration(time.Until(reset)))
+	}
+
+	field("Directory", info.Directory)
+	field("Provider", info.Provider)
+	field("Repository", info.Repo)
+	pr := ""
+	if info.PRNumber > 0 {
+		pr = fmt.Sprintf("#%d", info.PRNumber)
+	}
+	field("Pull request", pr)
+	field("Branch", info.Branch)
+	if info.Provider == "github" {
+		field("API host", info.Host)
+		field("Token from", info.TokenSource)
+		field("User", info.User)
+		field("REST quota", formatRate(info.RateLimit))
+		field("GraphQL quota", formatRate(info.GraphQLLimit))
+	}
+	field("Backend", info.Backend)
+	field("gh", info.GhPath)
+
+	if len(info.Errors) > 0 {
+		fmt.Println()
+		for _, err := range info.Errors {
+			fmt.Printf("%s!%s %s\n", colorYellow, colorReset, strings.TrimSpace(err))
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// stderrDiagnostics writes the warnings of one invocation. Like the request
+// budget it covers the whole process, so it's configured from the options
+// once they've been parsed.
+type stderrDiagnostics struct {
+	// mu keeps lines written from concurrent fetches whole
+	mu    sync.Mutex
+	quiet bool
+}
+
+var diagnostics = &stderrDiagnostics{}
+
+// quietWarnings silences warnings for --no-warnings. Errors that stop the run
+// are still reported.
+func quietWarnings(quiet bool) {
+	diagnostics.mu.Lock()
+	defer diagnostics.mu.Unlock()
+	diagnostics.quiet = quiet
+}
+
+// machineFormats are the --format values read by other programs, which get
+// no escape codes even when colors are forced
+var machineFormats = map[string]bool{
+	"json": true, "ndjson": true, "csv": true, "tsv": true, "junit": true, "html": true,
+	"sarif": true, "rdjson": true, "actions": true, "quickfix": true,
+}
+
+// warnf reports a problem that doesn't stop the run. Diagnostics only ever
+// go to stderr, so they can't end up inside JSON or other output on stdout.
+func warnf(format string, args ...any) {
+	diagnostics.mu.Lock()
+	defer diagnostics.mu.Unlock()
+	if diagnostics.quiet {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+}
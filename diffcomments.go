@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// PRFile is a file changed by the PR, along with its unified diff patch
+type PRFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Patch            string `json:"patch,omitempty"`
+}
+
+// runDiffComments prints the full PR diff with every review comment shown
+// inline beneath the line it was left on.
+func runDiffComments(args []string) {
+	opts := parseArgs(args)
+	client := resolvePR(opts)
+
+	feedback, err := fetchPRDetails(client, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+
+	comments, err := fetchReviewComments(client, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+
+	for i := range comments {
+		comments[i].DiscussionURL = discussionURL(feedback.URL, comments[i])
+	}
+
+	files, err := fetchPRFiles(client, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR diff: %v\n", err)
+		os.Exit(1)
+	}
+
+	printDiffComments(feedback, files, comments)
+}
+
+// fetchPRFiles returns every file changed by the PR
+func fetchPRFiles(client *api.RESTClient, repo string, prNumber int) ([]PRFile, error) {
+	files, err := getAllPages[PRFile](client, fmt.Sprintf("repos/%s/pulls/%d/files", repo, prNumber))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PR files: %w", err)
+	}
+	return files, nil
+}
+
+// diffAnchor identifies a line in a file's diff that a comment can point at
+type diffAnchor struct {
+	side string
+	line int
+}
+
+func printDiffComments(feedback *PRFeedback, files []PRFile, comments []ReviewComment) {
+	width := terminalWidth()
+	separator := strings.Repeat("─", separatorWidth())
+
+	fmt.Printf("%s%s #%d%s\n", colorBold, feedback.Title, feedback.PRNumber, colorReset)
+	fmt.Printf("%s%s%s\n", colorGray, feedback.URL, colorReset)
+
+	// Order comments chronologically so replies follow their parent
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt < comments[j].CreatedAt
+	})
+
+	byPath := make(map[string][]ReviewComment)
+	for _, comment := range comments {
+		byPath[comment.Path] = append(byPath[comment.Path], comment)
+	}
+
+	// Replies inherit their root comment's anchor
+	roots := make(map[int]ReviewComment)
+	for _, comment := range comments {
+		if comment.InReplyTo == nil {
+			roots[comment.ID] = comment
+		}
+	}
+
+	for _, file := range files {
+		fmt.Println("\n" + separator)
+		fmt.Printf("%s%s%s", colorBold, file.Filename, colorReset)
+		if file.PreviousFilename != "" {
+			fmt.Printf(" %s(renamed from %s)%s", colorGray, file.PreviousFilename, colorReset)
+		}
+		fmt.Printf(" %s+%d%s %s-%d%s\n", colorGreen, file.Additions, colorReset, colorRed, file.Deletions, colorReset)
+		fmt.Println(separator)
+
+		anchored := make(map[diffAnchor][]ReviewComment)
+		var unanchored []ReviewComment
+		for _, comment := range byPath[file.Filename] {
+			anchor, ok := commentAnchor(comment, roots)
+			if ok {
+				anchored[anchor] = append(anchored[anchor], comment)
+			} else {
+				unanchored = append(unanchored, comment)
+			}
+		}
+
+		// Comments on the file as a whole come before the patch
+		var fileLevel []ReviewComment
+		var outdated []ReviewComment
+		for _, comment := range unanchored {
+			if comment.SubjectType == "file" {
+				fileLevel = append(fileLevel, comment)
+			} else {
+				outdated = append(outdated, comment)
+			}
+		}
+		for _, comment := range fileLevel {
+			printInlineComment(comment, width)
+		}
+
+		if file.Patch == "" {
+			fmt.Printf("%s(no textual diff available)%s\n", colorGray, colorReset)
+		}
+
+		printed := make(map[diffAnchor]bool)
+		oldLine, newLine := 0, 0
+		for _, line := range strings.Split(file.Patch, "\n") {
+			if line == "" {
+				continue
+			}
+
+			var anchors []diffAnchor
+			switch line[0] {
+			case '@':
+				oldLine, newLine = parseHunkHeader(line)
+				fmt.Printf("%s%s%s\n", colorCyan, line, colorReset)
+				continue
+			case '+':
+				fmt.Printf("%s%s%s\n", colorGreen, line, colorReset)
+				anchors = []diffAnchor{{"RIGHT", newLine}}
+				newLine++
+			case '-':
+				fmt.Printf("%s%s%s\n", colorRed, line, colorReset)
+				anchors = []diffAnchor{{"LEFT", oldLine}}
+				oldLine++
+			case '\\':
+				fmt.Printf("%s%s%s\n", colorGray, line, colorReset)
+				continue
+			default:
+				fmt.Println(line)
+				anchors = []diffAnchor{{"RIGHT", newLine}, {"LEFT", oldLine}}
+				oldLine++
+				newLine++
+			}
+
+			for _, anchor := range anchors {
+				if printed[anchor] {
+					continue
+				}
+				printed[anchor] = true
+				for _, comment := range anchored[anchor] {
+					printInlineComment(comment, width)
+				}
+			}
+		}
+
+		// Anything left over points at lines no longer in the diff
+		for anchor, list := range anchored {
+			if !printed[anchor] {
+				outdated = append(outdated, list...)
+			}
+		}
+		if len(outdated) > 0 {
+			sort.SliceStable(outdated, func(i, j int) bool {
+				return outdated[i].CreatedAt < outdated[j].CreatedAt
+			})
+			fmt.Printf("\n%sOutdated comments%s\n", colorYellow, colorReset)
+			for _, comment := range outdated {
+				printInlineComment(comment, width)
+			}
+		}
+		delete(byPath, file.Filename)
+	}
+
+	// Comments on files that are no longer part of the PR
+	var paths []string
+	for path := range byPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Println("\n" + separator)
+		fmt.Printf("%s%s%s %s(no longer changed by this PR)%s\n", colorBold, path, colorReset, colorGray, colorReset)
+		fmt.Println(separator)
+		for _, comment := range byPath[path] {
+			printInlineComment(comment, width)
+		}
+	}
+}
+
+// commentAnchor returns the diff line a comment is attached to. Comments whose
+// line is no longer part of the diff have no anchor.
+func commentAnchor(comment ReviewComment, roots map[int]ReviewComment) (diffAnchor, bool) {
+	if comment.InReplyTo != nil {
+		if root, ok := roots[*comment.InReplyTo]; ok {
+			comment = root
+		}
+	}
+	if comment.Line == nil || *comment.Line <= 0 {
+		return diffAnchor{}, false
+	}
+	side := comment.Side
+	if side == "" {
+		side = "RIGHT"
+	}
+	return diffAnchor{side: side, line: *comment.Line}, true
+}
+
+// parseHunkHeader returns the starting old and new line numbers of a hunk
+// header like "@@ -12,7 +12,9 @@ func main() {".
+func parseHunkHeader(header string) (int, int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0
+	}
+	parse := func(field string) int {
+		field = strings.TrimLeft(field, "-+")
+		if i := strings.Index(field, ","); i >= 0 {
+			field = field[:i]
+		}
+		n, _ := strconv.Atoi(field)
+		return n
+	}
+	return parse(fields[1]), parse(fields[2])
+}
+
+func printInlineComment(comment ReviewComment, width int) {
+	bar := colorYellow + "    │ " + colorReset
+	bodyWidth := 0
+	if width > 0 {
+		bodyWidth = width - 6
+	}
+
+	fmt.Printf("%s%s%s%s", bar, colorBold+authorColor(comment.Author), hyperlink(comment.DiscussionURL, comment.Author), colorReset)
+	if comment.InReplyTo != nil {
+		fmt.Printf(" %sreplied%s", colorGray, colorReset)
+	} else if comment.PositionState == "outdated" {
+		fmt.Printf(" %son original line %d%s", colorGray, *comment.OriginalLine, colorReset)
+	}
+	if comment.CreatedAt != "" {
+		if t, err := parseTime(comment.CreatedAt); err == nil {
+			fmt.Printf(" • %s%s%s", colorGray, formatTimeAgo(now().Sub(t)), colorReset)
+		}
+	}
+	fmt.Println()
+	for _, line := range wrapBody(comment.Body, bodyWidth) {
+		fmt.Printf("%s%s\n", bar, line)
+	}
+}
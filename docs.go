@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DocLink points the threads on files under a path prefix at documentation
+// that gives authors and reviewers shared context, e.g. a runbook or ADR
+type DocLink struct {
+	Path  string `yaml:"path" json:"path"`
+	URL   string `yaml:"url" json:"url"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty"`
+}
+
+// attachDocs sets the relevant docs of every thread on a file under one of
+// the links' paths
+func attachDocs(feedback *PRFeedback, links []DocLink) {
+	if len(links) == 0 {
+		return
+	}
+	for i, comment := range feedback.Comments {
+		if comment.Path == "" {
+			continue
+		}
+		for _, link := range links {
+			// A link's path is a file or a directory, so "migrations"
+			// doesn't match migrations-old/
+			if inScope(strings.Trim(link.Path, "/"), comment.Path) {
+				feedback.Comments[i].Docs = append(feedback.Comments[i].Docs, link)
+			}
+		}
+	}
+}
+
+// printDocs prints a thread's relevant docs below its location
+func printDocs(docs []DocLink) {
+	if len(docs) == 0 {
+		return
+	}
+	names := make([]string, len(docs))
+	for i, doc := range docs {
+		names[i] = doc.URL
+		if doc.Title != "" {
+			names[i] = fmt.Sprintf("%s (%s)", doc.Title, doc.URL)
+		}
+	}
+	fmt.Printf("%sRelevant docs: %s%s\n", colorGray, strings.Join(names, ", "), colorReset)
+}
+
+// writeMarkdownDocs writes a thread's relevant docs as Markdown links
+func writeMarkdownDocs(w io.Writer, docs []DocLink) {
+	if len(docs) == 0 {
+		return
+	}
+	links := make([]string, len(docs))
+	for i, doc := range docs {
+		title := doc.Title
+		if title == "" {
+			title = doc.URL
+		}
+		links[i] = fmt.Sprintf("[%s](%s)", title, doc.URL)
+	}
+	fmt.Fprintf(w, "\n_Relevant docs: %s_\n", strings.Join(links, ", "))
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// DoctorCheck is the outcome of one prerequisite check run by doctor
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok, warn or fail
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// runDoctor checks everything gh pr-feedback needs to work in the current
+// directory and says how to fix what's missing. It exits non-zero when a
+// check fails.
+func runDoctor(args []string) {
+	opts := parseArgs(args)
+	changeDir(opts)
+
+	var checks []DoctorCheck
+	add := func(name, status, detail, fix string) {
+		checks = append(checks, DoctorCheck{Name: name, Status: status, Detail: detail, Fix: fix})
+	}
+
+	if path, err := exec.LookPath("gh"); err != nil {
+		add("gh installed", "fail", "gh is not on PATH", "Install the GitHub CLI from https://cli.github.com")
+	} else {
+		version := path
+		if output, err := exec.Command("gh", "--version").Output(); err == nil {
+			version = firstLine(string(output))
+		}
+		add("gh installed", "ok", version, "")
+	}
+
+	host, _ := auth.DefaultHost()
+	if token, source := auth.TokenForHost(host); currentAppAuth() != nil {
+		add("authenticated", "ok", "as a GitHub App installation", "")
+	} else if token == "" {
+		add("authenticated", "fail", "no token for "+host, "Run `gh auth login --hostname "+host+"`")
+	} else {
+		add("authenticated", "ok", fmt.Sprintf("%s (token from %s)", host, source), "")
+	}
+
+	client, err := api.NewRESTClient(clientOptions())
+	if err != nil {
+		add("API reachable", "fail", err.Error(), "Check `gh auth status` and your network or proxy settings")
+		client = nil
+	} else if response, err := client.Request("GET", "rate_limit", nil); err != nil {
+		add("API reachable", "fail", err.Error(), "Check `gh auth status` and your network or proxy settings")
+		client = nil
+	} else {
+		response.Body.Close()
+		add("API reachable", "ok", "https://"+host, "")
+		checks = append(checks, checkScopes(client))
+	}
+
+	repo := opts.repoName
+	if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err != nil {
+		add("git repository", "fail", "not inside a git repository", "Run from a checkout, or pass a directory or --repo owner/name")
+	} else {
+		add("git repository", "ok", strings.TrimSpace(string(output)), "")
+		if repo == "" {
+			if repo, err = getCurrentRepo(); err != nil {
+				add("GitHub repository", "warn", err.Error(), "Add a GitHub remote, or pass --repo owner/name")
+			} else {
+				add("GitHub repository", "ok", repo, "")
+			}
+		}
+	}
+
+	checks = append(checks, checkConfig(client, repo))
+
+	if opts.jsonOutput {
+		printJSON(opts, checks)
+	} else {
+		printDoctor(checks)
+	}
+	for _, check := range checks {
+		if check.Status == "fail" {
+			os.Exit(1)
+		}
+	}
+}
+
+// checkScopes verifies a classic token can resolve threads over GraphQL,
+// which needs the repo scope. Fine-grained and app tokens don't report
+// scopes, so they can only be checked by trying.
+func checkScopes(client *api.RESTClient) DoctorCheck {
+	check := DoctorCheck{Name: "token scopes"}
+	response, err := client.Request("GET", "user", nil)
+	if err != nil {
+		check.Status, check.Detail = "warn", "could not read token scopes: "+err.Error()
+		return check
+	}
+	response.Body.Close()
+
+	header, ok := response.Header["X-Oauth-Scopes"]
+	if !ok {
+		check.Status, check.Detail = "ok", "scopes aren't reported for this token type; resolving threads needs pull request write access"
+		return check
+	}
+	scopes := make(map[string]bool)
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		scopes[strings.TrimSpace(scope)] = true
+	}
+	switch {
+	case scopes["repo"]:
+		check.Status, check.Detail = "ok", strings.Join(header, ", ")
+	case scopes["public_repo"]:
+		check.Status, check.Detail = "warn", "public_repo only; threads on private repositories can't be resolved"
+		check.Fix = "Run `gh auth refresh --scopes repo`"
+	default:
+		check.Status, check.Detail = "fail", "missing the repo scope needed to read and resolve review threads"
+		check.Fix = "Run `gh auth refresh --scopes repo`"
+	}
+	return check
+}
+
+// checkConfig loads the repository config and validates the patterns in it
+func checkConfig(client *api.RESTClient, repo string) DoctorCheck {
+	check := DoctorCheck{Name: "config"}
+	config, err := loadConfig(client, repo)
+	if err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		check.Fix = "Fix the YAML in " + configPath
+		return check
+	}
+
+	var problems []string
+	for _, rule := range config.Gate {
+		if rule.Comments == nil && rule.Checks == nil {
+			problems = append(problems, fmt.Sprintf("gate rule %q has neither comments nor checks", rule.Name))
+		}
+		if rule.Comments != nil && rule.Comments.Match != "" {
+			if _, err := regexp.Compile(rule.Comments.Match); err != nil {
+				problems = append(problems, fmt.Sprintf("gate rule %q: %v", rule.Name, err))
+			}
+		}
+	}
+	for _, analyzer := range config.Analyzers {
+		switch {
+		case analyzer.Pattern == "" && analyzer.Command == "":
+			problems = append(problems, fmt.Sprintf("analyzer %q has neither pattern nor command", analyzer.Name))
+		case analyzer.Pattern != "":
+			if _, err := regexp.Compile(analyzer.Pattern); err != nil {
+				problems = append(problems, fmt.Sprintf("analyzer %q: %v", analyzer.Name, err))
+			}
+		}
+	}
+	for _, rule := range config.Followup {
+		if rule.Match != "" {
+			if _, err := regexp.Compile(rule.Match); err != nil {
+				problems = append(problems, fmt.Sprintf("follow-up rule %q: %v", rule.Label, err))
+			}
+		}
+	}
+	for _, assignment := range config.Renderers {
+		if _, ok := commentRenderers[assignment.Renderer]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown renderer %q for %s", assignment.Renderer, assignment.Author))
+		}
+	}
+	if config.PingTemplate != "" {
+		if _, err := parsePingTemplate(config.PingTemplate); err != nil {
+			problems = append(problems, "ping_template: "+err.Error())
+		}
+	}
+	if config.AssignTemplate != "" {
+		if _, err := parseAssignTemplate(config.AssignTemplate); err != nil {
+			problems = append(problems, "assign_template: "+err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		check.Status, check.Detail = "fail", strings.Join(problems, "; ")
+		check.Fix = "Fix the entries in " + configPath
+		return check
+	}
+	check.Status, check.Detail = "ok", configPath+" from the default branch, or built-in defaults"
+	if config.local {
+		check.Detail = configPath + " from the local checkout"
+	}
+	return check
+}
+
+func printDoctor(checks []DoctorCheck) {
+	for _, check := range checks {
+		symbol, color := "✓", colorGreen
+		switch check.Status {
+		case "warn":
+			symbol, color = "!", colorYellow
+		case "fail":
+			symbol, color = "✗", colorRed
+		}
+		fmt.Printf("%s%s%s %s%s%s", color, symbol, colorReset, colorBold, check.Name, colorReset)
+		if check.Detail != "" {
+			fmt.Printf(" %s%s%s", colorGray, check.Detail, colorReset)
+		}
+		fmt.Println()
+		if check.Fix != "" {
+			fmt.Printf("  → %s\n", check.Fix)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Checks count as slow when they took slowCheckFactor times their usual
+// duration and at least slowCheckMargin longer, so short jobs jittering by a
+// few seconds aren't flagged
+const (
+	slowCheckFactor  = 2.0
+	slowCheckMargin  = 2 * time.Minute
+	slowCheckSamples = 5
+)
+
+// SlowCheck is an Actions job on the PR that took much longer than it
+// usually does on the default branch
+type SlowCheck struct {
+	Name         string `json:"name"`
+	WorkflowName string `json:"workflow_name,omitempty"`
+	DetailsURL   string `json:"details_url,omitempty"`
+	// Seconds is how long the job took on the PR, and UsualSeconds its
+	// average over recent successful runs on the default branch
+	Seconds      int `json:"seconds"`
+	UsualSeconds int `json:"usual_seconds"`
+}
+
+type jobKey struct{ workflow, name string }
+
+// attachSlowChecks flags the PR's Actions jobs whose duration regressed
+// against recent successful runs on the default branch, since a PR that
+// makes CI slower is worth hearing about too. The jobs come from the status
+// checks already fetched; the comparison costs a request per workflow run,
+// so it's only done for --slow-checks. It's best-effort: any request failing
+// just means nothing is flagged.
+func attachSlowChecks(client *api.RESTClient, feedback *PRFeedback) {
+	current := make(map[jobKey]prCheck)
+	for _, check := range feedback.jobs {
+		if check.WorkflowName == "" || extractRunID(check.DetailsURL) == "" || check.CompletedAt == "" {
+			continue
+		}
+		current[jobKey{check.WorkflowName, check.Name}] = check
+	}
+	if len(current) == 0 {
+		return
+	}
+
+	usual := usualJobDurations(client, feedback.Repo, current)
+	for key, check := range current {
+		average, ok := usual[key]
+		if !ok {
+			continue
+		}
+		start, err1 := parseTime(check.StartedAt)
+		end, err2 := parseTime(check.CompletedAt)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		took := end.Sub(start)
+		if float64(took) >= slowCheckFactor*float64(average) && took-average >= slowCheckMargin {
+			feedback.SlowChecks = append(feedback.SlowChecks, SlowCheck{
+				Name:         check.Name,
+				WorkflowName: check.WorkflowName,
+				DetailsURL:   check.DetailsURL,
+				Seconds:      int(took.Seconds()),
+				UsualSeconds: int(average.Seconds()),
+			})
+		}
+	}
+	sort.Slice(feedback.SlowChecks, func(i, j int) bool {
+		a, b := feedback.SlowChecks[i], feedback.SlowChecks[j]
+		return a.Seconds-a.UsualSeconds > b.Seconds-b.UsualSeconds
+	})
+}
+
+// usualJobDurations averages how long each of the wanted jobs took over the
+// latest successful runs of its workflow on the default branch
+func usualJobDurations(client *api.RESTClient, repo string, wanted map[jobKey]prCheck) map[jobKey]time.Duration {
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s", repo), &repository); err != nil || repository.DefaultBranch == "" {
+		return nil
+	}
+	var runs struct {
+		WorkflowRuns []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"workflow_runs"`
+	}
+	endpoint := fmt.Sprintf("repos/%s/actions/runs?branch=%s&status=success&exclude_pull_requests=true&per_page=100",
+		repo, url.QueryEscape(repository.DefaultBranch))
+	if err := client.Get(endpoint, &runs); err != nil {
+		return nil
+	}
+
+	workflows := make(map[string]bool)
+	for key := range wanted {
+		workflows[key.workflow] = true
+	}
+	totals := make(map[jobKey]time.Duration)
+	counts := make(map[jobKey]int)
+	sampled := make(map[string]int)
+	for _, run := range runs.WorkflowRuns {
+		if !workflows[run.Name] || sampled[run.Name] >= slowCheckSamples {
+			continue
+		}
+		sampled[run.Name]++
+
+		var jobs struct {
+			Jobs []struct {
+				Name        string `json:"name"`
+				Conclusion  string `json:"conclusion"`
+				StartedAt   string `json:"started_at"`
+				CompletedAt string `json:"completed_at"`
+			} `json:"jobs"`
+		}
+		if err := client.Get(fmt.Sprintf("repos/%s/actions/runs/%d/jobs?per_page=100", repo, run.ID), &jobs); err != nil {
+			// Once --max-requests is used up the rest would be skipped too
+			if budgetSkipped(err) {
+				break
+			}
+			continue
+		}
+		for _, job := range jobs.Jobs {
+			key := jobKey{run.Name, job.Name}
+			if _, ok := wanted[key]; !ok || job.Conclusion != "success" {
+				continue
+			}
+			start, err1 := parseTime(job.StartedAt)
+			end, err2 := parseTime(job.CompletedAt)
+			if err1 != nil || err2 != nil || end.Before(start) {
+				continue
+			}
+			totals[key] += end.Sub(start)
+			counts[key]++
+		}
+	}
+
+	usual := make(map[jobKey]time.Duration, len(totals))
+	for key, total := range totals {
+		usual[key] = total / time.Duration(counts[key])
+	}
+	return usual
+}
+
+// printSlowChecks lists the checks whose duration regressed
+func printSlowChecks(checks []SlowCheck, separator string) {
+	if len(checks) == 0 {
+		return
+	}
+	fmt.Println("\n" + separator + "\n")
+	fmt.Printf("%sSlow Checks%s\n\n", colorBold, colorReset)
+	for _, check := range checks {
+		took := time.Duration(check.Seconds) * time.Second
+		usual := time.Duration(check.UsualSeconds) * time.Second
+		fmt.Printf("%s⏱%s %s took %s, usually %s", colorYellow, colorReset, check.Name, formatDuration(took), formatDuration(usual))
+		if check.WorkflowName != "" {
+			fmt.Printf(" %s(%s on the default branch)%s", colorGray, check.WorkflowName, colorReset)
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// editStop is one place to visit while addressing feedback
+type editStop struct {
+	Path    string
+	Line    int
+	Author  string
+	Summary string
+}
+
+// editPlan orders the line comments for a fix session: grouped by file so
+// each file is opened once, then by line within it
+func editPlan(feedback *PRFeedback) []editStop {
+	var stops []editStop
+	for _, comment := range feedback.Comments {
+		if comment.Path == "" || comment.Generated || comment.State == "resolved" {
+			continue
+		}
+		line := 1
+		if comment.Line != nil && *comment.Line > 0 {
+			line = *comment.Line
+		} else if comment.OriginalLine != nil && *comment.OriginalLine > 0 {
+			line = *comment.OriginalLine
+		}
+		stops = append(stops, editStop{
+			Path:    comment.Path,
+			Line:    line,
+			Author:  comment.Author,
+			Summary: firstLine(comment.Body),
+		})
+	}
+	sort.SliceStable(stops, func(i, j int) bool {
+		if stops[i].Path != stops[j].Path {
+			return stops[i].Path < stops[j].Path
+		}
+		return stops[i].Line < stops[j].Line
+	})
+	return stops
+}
+
+// printEditPlan prints the edit plan as a list, a vim quickfix file (for
+// `vim -q`) or a script of `code -g` commands
+func printEditPlan(feedback *PRFeedback, editor string) {
+	stops := editPlan(feedback)
+	switch editor {
+	case "vim":
+		for _, stop := range stops {
+			fmt.Printf("%s:%d:1: %s: %s\n", stop.Path, stop.Line, stop.Author, stop.Summary)
+		}
+	case "code":
+		fmt.Println("#!/bin/sh")
+		fmt.Println("# Run from the repository root; each step opens the next comment")
+		for i, stop := range stops {
+			fmt.Printf("\n# %d/%d %s: %s\n", i+1, len(stops), stop.Author, stop.Summary)
+			fmt.Printf("code -r -g %s\n", shellQuote(stop.Path+":"+strconv.Itoa(stop.Line)))
+			if i < len(stops)-1 {
+				fmt.Println(`printf 'Press enter for the next comment'; read _`)
+			}
+		}
+	default:
+		path := ""
+		for _, stop := range stops {
+			if stop.Path != path {
+				if path != "" {
+					fmt.Println()
+				}
+				path = stop.Path
+				fmt.Printf("%s%s%s\n", colorBlue, path, colorReset)
+			}
+			fmt.Printf("  %s%5d%s  %s%s%s: %s\n", colorGray, stop.Line, colorReset, authorColor(stop.Author), stop.Author, colorReset, stop.Summary)
+		}
+	}
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
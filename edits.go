@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// maxDiffTokens bounds the word diff; longer edits are shown as a full
+// replacement instead
+const maxDiffTokens = 2000
+
+// commentSnapshot is the last seen version of a comment, kept in
+// .git/gh-pr-feedback/<pr>/snapshot.json to spot edits between runs
+type commentSnapshot struct {
+	Body      string `json:"body"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func loadSnapshot(prNumber int) (map[int]commentSnapshot, error) {
+	dir, err := stateDir(prNumber)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(map[int]commentSnapshot)
+	data, err := os.ReadFile(filepath.Join(dir, "snapshot.json"))
+	if os.IsNotExist(err) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// updateSnapshot records the current version of every comment. Comments not
+// in this fetch, e.g. beyond a shallow fetch, keep their previous snapshot.
+func updateSnapshot(snapshot map[int]commentSnapshot, feedback *PRFeedback) {
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for _, comment := range comments {
+			snapshot[comment.ID] = commentSnapshot{Body: comment.Body, UpdatedAt: comment.UpdatedAt}
+		}
+	}
+}
+
+// saveSnapshot writes the snapshot for the next run to compare against
+func saveSnapshot(prNumber int, snapshot map[int]commentSnapshot) error {
+	dir, err := stateDir(prNumber)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "snapshot.json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// detectEdits sets PreviousBody on comments edited since the snapshot was
+// taken
+func detectEdits(feedback *PRFeedback, snapshot map[int]commentSnapshot) {
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for i := range comments {
+			previous, ok := snapshot[comments[i].ID]
+			if !ok || previous.Body == comments[i].Body {
+				continue
+			}
+			updated, err := parseTime(comments[i].UpdatedAt)
+			if err != nil {
+				continue
+			}
+			if seen, err := parseTime(previous.UpdatedAt); err == nil && !updated.After(seen) {
+				continue
+			}
+			comments[i].PreviousBody = previous.Body
+		}
+	}
+}
+
+// diffOp is one run of a word diff: unchanged, deleted ('-') or inserted ('+')
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// wordDiff compares two texts word by word, keeping whitespace attached to
+// the surrounding runs so the result reads like the original text.
+func wordDiff(before, after string) []diffOp {
+	a, b := diffTokens(before), diffTokens(after)
+	if len(a) > maxDiffTokens || len(b) > maxDiffTokens {
+		return []diffOp{{'-', before}, {'+', after}}
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	add := func(kind byte, text string) {
+		if n := len(ops); n > 0 && ops[n-1].kind == kind {
+			ops[n-1].text += text
+			return
+		}
+		ops = append(ops, diffOp{kind, text})
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(' ', a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add('-', a[i])
+			i++
+		default:
+			add('+', b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add('-', a[i])
+	}
+	for ; j < len(b); j++ {
+		add('+', b[j])
+	}
+	return ops
+}
+
+// diffTokens splits text into alternating runs of words and whitespace
+func diffTokens(text string) []string {
+	var tokens []string
+	start, space := 0, false
+	for i, r := range text {
+		if i > start && unicode.IsSpace(r) != space {
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+		space = unicode.IsSpace(r)
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+// printEdit shows a comment's body as a word diff against its previous
+// version, in the style of git diff --word-diff
+func printEdit(comment ReviewComment) {
+	fmt.Printf("%sEdited since last viewed:%s\n", colorGray, colorReset)
+	var out strings.Builder
+	for _, op := range wordDiff(comment.PreviousBody, comment.Body) {
+		switch op.kind {
+		case '-':
+			out.WriteString(colorRed + "[-" + op.text + "-]" + colorReset)
+		case '+':
+			out.WriteString(colorGreen + "{+" + op.text + "+}" + colorReset)
+		default:
+			out.WriteString(op.text)
+		}
+	}
+	fmt.Println(strings.TrimRight(out.String(), "\n"))
+}
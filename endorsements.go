@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// reactions is the reaction rollup the REST API includes with comments
+type reactions struct {
+	ThumbsUp   int `json:"+1"`
+	ThumbsDown int `json:"-1"`
+	Laugh      int `json:"laugh"`
+	Hooray     int `json:"hooray"`
+	Confused   int `json:"confused"`
+	Heart      int `json:"heart"`
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
+}
+
+// counts returns the reactions given, keyed by their REST names
+func (r reactions) counts() map[string]int {
+	counts := make(map[string]int)
+	for name, count := range map[string]int{
+		"+1": r.ThumbsUp, "-1": r.ThumbsDown, "laugh": r.Laugh, "hooray": r.Hooray,
+		"confused": r.Confused, "heart": r.Heart, "rocket": r.Rocket, "eyes": r.Eyes,
+	} {
+		if count > 0 {
+			counts[name] = count
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	return counts
+}
+
+// sortByEndorsements moves the threads other reviewers have 👍-reacted to to
+// the front, most endorsed first, so feedback with consensus behind it is
+// read first. Threads with equal endorsements keep their order.
+func sortByEndorsements(feedback *PRFeedback) {
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		sort.SliceStable(comments, func(i, j int) bool {
+			return comments[i].Endorsements > comments[j].Endorsements
+		})
+	}
+}
+
+// formatEndorsements renders the endorsement count for a comment header
+func formatEndorsements(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %s• 👍 %d%s", colorGreen, count, colorReset)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// graphqlPointsPerPR is roughly what fetching one PR costs against the
+// GraphQL quota: its review threads, review decision and minimized comments,
+// plus the status checks and required checks gh looks up
+const graphqlPointsPerPR = 5
+
+const rateLimitQuery = `query { rateLimit { limit remaining resetAt } }`
+
+// graphqlRateLimit is the GraphQL quota as reported by its rateLimit object
+type graphqlRateLimit struct {
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	ResetAt   string `json:"resetAt"`
+}
+
+func fetchGraphQLRateLimit() (*graphqlRateLimit, error) {
+	var response struct {
+		RateLimit graphqlRateLimit `json:"rateLimit"`
+	}
+	if err := createGraphQLClient().Do(rateLimitQuery, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch GraphQL rate limit: %w", err)
+	}
+	return &response.RateLimit, nil
+}
+
+// estimateScan prints what scanning prs PRs will cost against the GraphQL
+// quota, and aborts before any of it is spent if the quota can't cover it
+func estimateScan(prs int) {
+	limit, err := fetchGraphQLRateLimit()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cost := prs * graphqlPointsPerPR
+	resetIn := "unknown"
+	if reset, err := parseTime(limit.ResetAt); err == nil {
+		resetIn = formatDuration(reset.Sub(now()))
+	}
+	fmt.Fprintf(os.Stderr, "Scanning %d PR(s) will cost about %d GraphQL point(s); %d/%d remaining, resets in %s\n",
+		prs, cost, limit.Remaining, limit.Limit, resetIn)
+
+	if cost > limit.Remaining {
+		fmt.Fprintf(os.Stderr, "Error: the scan would exhaust the GraphQL quota\n")
+		fmt.Fprintf(os.Stderr, "Narrow it with --repo or --base, cap it with --max-requests, or wait %s for the quota to reset\n", resetIn)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// historyColumns are the columns of `export --hist`, in order
+var historyColumns = []string{
+	"repo", "pr_number", "pr_title", "pr_state", "pr_author", "thread_id",
+	"comment_id", "path", "line", "author", "created_at", "comments",
+	"last_comment_at", "resolved", "resolved_by", "outdated",
+	"resolution_hours", "body",
+}
+
+// historyPR is a PR listed by `export --hist`
+type historyPR struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	State     string `json:"state"`
+	MergedAt  string `json:"merged_at"`
+	UpdatedAt string `json:"updated_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// runExport dumps every review thread of the repository's PRs updated since
+// a date, resolved or not, for retrospectives on how reviews go.
+func runExport(args []string) {
+	var since, format, output string
+	var byLabel bool
+	format = "csv"
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if (arg == "--hist" || arg == "--format" || arg == "--output" || arg == "-o") && i+1 >= len(args) {
+			fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+			os.Exit(1)
+		}
+		switch arg {
+		case "--hist":
+			since = args[i+1]
+			i++
+		case "--format":
+			format = args[i+1]
+			i++
+		case "--output", "-o":
+			output = args[i+1]
+			i++
+		case "--by-label":
+			byLabel = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if byLabel {
+		runLabelExport(rest, output)
+		return
+	}
+	if since == "" || (format != "csv" && format != "sql") {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback export --hist <age|date> [--format csv|sql] [-o file] [--repo owner/name]\n")
+		fmt.Fprintf(os.Stderr, "       gh pr-feedback export --by-label [-o dir] [pr-number]\n")
+		os.Exit(1)
+	}
+	cutoff, err := parseSince("--hist", since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := parseArgs(rest)
+	client := newClient(opts)
+	if opts.repoName == "" {
+		if opts.repoName, err = getCurrentRepo(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	pulls, err := listPRsUpdatedSince(client, opts.repoName, cutoff)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	gql := createGraphQLClient()
+	refs := make([]prRef, len(pulls))
+	for i, pull := range pulls {
+		refs[i] = prRef{Repo: opts.repoName, Number: pull.Number}
+	}
+	threads := make([][]reviewThread, len(refs))
+	forEachPR(refs, func(i int, ref prRef) {
+		list, err := fetchReviewThreads(gql, ref.Repo, ref.Number)
+		if err != nil {
+			warnf("failed to fetch threads for %s#%d: %v", ref.Repo, ref.Number, err)
+			return
+		}
+		threads[i] = list
+	})
+
+	// Old comment bodies must not leave GitHub under some retention
+	// policies, here as in every other output
+	var retentionCutoff time.Time
+	if opts.retention > 0 {
+		retentionCutoff = now().Add(-opts.retention)
+	}
+	var rows [][]string
+	redacted := 0
+	for i, pull := range pulls {
+		for _, thread := range threads[i] {
+			if created, err := parseTime(thread.Comment.CreatedAt); err == nil && created.Before(retentionCutoff) {
+				thread.Comment.Body = ""
+				redacted++
+			}
+			rows = append(rows, historyRow(opts.repoName, pull, thread))
+		}
+	}
+	if redacted > 0 {
+		fmt.Fprintf(os.Stderr, "Redacted %d comment(s) older than the retention window\n", redacted)
+	}
+
+	out := io.Writer(os.Stdout)
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+	if format == "sql" {
+		err = writeHistorySQL(out, rows)
+	} else {
+		err = writeHistoryCSV(out, rows)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d thread(s) from %d PR(s)\n", len(rows), len(pulls))
+}
+
+// parseSince accepts an age such as 90d or a date such as 2024-01-31 for
+// flag
+func parseSince(flag, value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	age, err := parseAge(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q, expected an age such as 90d or a date such as 2024-01-31", flag, value)
+	}
+	return now().Add(-age), nil
+}
+
+// errPastCutoff stops listing PRs once they were last updated before the cutoff
+var errPastCutoff = errors.New("past the cutoff")
+
+// listPRsUpdatedSince pages through the repository's PRs, most recently
+// updated first, until they're older than cutoff
+func listPRsUpdatedSince(client *api.RESTClient, repo string, cutoff time.Time) ([]historyPR, error) {
+	var pulls []historyPR
+	endpoint := fmt.Sprintf("repos/%s/pulls?state=all&sort=updated&direction=desc", repo)
+	err := eachPage(client, endpoint, func(batch []historyPR) error {
+		for _, pull := range batch {
+			if updated, err := parseTime(pull.UpdatedAt); err == nil && updated.Before(cutoff) {
+				return errPastCutoff
+			}
+			pulls = append(pulls, pull)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPastCutoff) {
+		return nil, fmt.Errorf("failed to list PRs: %w", err)
+	}
+	return pulls, nil
+}
+
+// historyRow flattens a thread into historyColumns. GitHub doesn't record
+// when a thread was resolved, so resolution time runs to the thread's last
+// comment.
+func historyRow(repo string, pull historyPR, thread reviewThread) []string {
+	state := pull.State
+	if pull.MergedAt != "" {
+		state = "merged"
+	}
+	line := ""
+	if thread.Comment.Line != nil {
+		line = strconv.Itoa(*thread.Comment.Line)
+	} else if thread.Comment.OriginalLine != nil {
+		line = strconv.Itoa(*thread.Comment.OriginalLine)
+	}
+	resolution := ""
+	if thread.IsResolved {
+		created, err1 := parseTime(thread.Comment.CreatedAt)
+		last, err2 := parseTime(thread.LastCommentAt)
+		if err1 == nil && err2 == nil {
+			resolution = strconv.FormatFloat(last.Sub(created).Hours(), 'f', 1, 64)
+		}
+	}
+	return []string{
+		repo, strconv.Itoa(pull.Number), pull.Title, state, pull.User.Login, thread.ID,
+		strconv.Itoa(thread.Comment.ID), thread.Comment.Path, line, thread.Comment.Author, thread.Comment.CreatedAt, strconv.Itoa(thread.Comments),
+		thread.LastCommentAt, strconv.FormatBool(thread.IsResolved), thread.ResolvedBy, strconv.FormatBool(thread.IsOutdated),
+		resolution, thread.Comment.Body,
+	}
+}
+
+// historyTextColumns hold text written by users, which writeHistoryCSV
+// escapes so a spreadsheet doesn't run it as a formula
+var historyTextColumns = map[string]bool{
+	"pr_title": true, "pr_author": true, "path": true, "author": true, "resolved_by": true, "body": true,
+}
+
+func writeHistoryCSV(out io.Writer, rows [][]string) error {
+	w := csv.NewWriter(out)
+	w.Write(historyColumns)
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			if historyTextColumns[historyColumns[i]] {
+				value = spreadsheetCell(value)
+			}
+			cells[i] = value
+		}
+		w.Write(cells)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeHistorySQL writes the rows as a script for `sqlite3 history.db`
+func writeHistorySQL(out io.Writer, rows [][]string) error {
+	numeric := map[string]bool{"pr_number": true, "comment_id": true, "line": true, "comments": true, "resolution_hours": true}
+	var b strings.Builder
+	b.WriteString("BEGIN;\nCREATE TABLE IF NOT EXISTS review_threads (\n")
+	for i, column := range historyColumns {
+		kind := "TEXT"
+		if numeric[column] {
+			kind = "NUMERIC"
+		}
+		if column == "thread_id" {
+			kind += " PRIMARY KEY"
+		}
+		fmt.Fprintf(&b, "  %s %s", column, kind)
+		if i < len(historyColumns)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(");\n")
+	for _, row := range rows {
+		values := make([]string, len(row))
+		for i, value := range row {
+			switch {
+			case value == "":
+				values[i] = "NULL"
+			case numeric[historyColumns[i]]:
+				values[i] = value
+			default:
+				values[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+			}
+		}
+		fmt.Fprintf(&b, "INSERT OR REPLACE INTO review_threads VALUES (%s);\n", strings.Join(values, ", "))
+	}
+	b.WriteString("COMMIT;\n")
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// feedbackColumns are the columns of --format csv and tsv, in order
+var feedbackColumns = []string{"id", "author", "file", "line", "created_at", "outdated", "body"}
+
+// writeFeedbackTable writes one row per unresolved comment, general ones
+// first, for dropping into a spreadsheet. comma is ',' for CSV or '\t' for
+// TSV; fields containing it or newlines are quoted either way, and ones a
+// spreadsheet would run as a formula are escaped.
+func writeFeedbackTable(out io.Writer, feedback *PRFeedback, comma rune) error {
+	w := csv.NewWriter(out)
+	w.Comma = comma
+	w.Write(feedbackColumns)
+	for _, comments := range [][]ReviewComment{feedback.GeneralIssues, feedback.Comments} {
+		for _, comment := range comments {
+			line := ""
+			if n := commentLine(comment); n > 0 {
+				line = strconv.Itoa(n)
+			}
+			w.Write([]string{
+				strconv.Itoa(comment.ID), spreadsheetCell(comment.Author), spreadsheetCell(comment.Path), line,
+				comment.CreatedAt, strconv.FormatBool(comment.Outdated), spreadsheetCell(comment.Body),
+			})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// spreadsheetCell prefixes values starting with =, +, -, @, a tab or a
+// carriage return with a quote, so a comment body can't be taken for a
+// formula when the file is opened
+func spreadsheetCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
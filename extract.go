@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CodeBlock is a fenced code block found in a comment body
+type CodeBlock struct {
+	CommentID int    `json:"comment_id"`
+	Author    string `json:"author"`
+	Path      string `json:"path,omitempty"`
+	Line      *int   `json:"line,omitempty"`
+	Language  string `json:"language,omitempty"`
+	File      string `json:"file"`
+	Code      string `json:"-"`
+}
+
+// languageExtensions maps fence info strings to file extensions
+var languageExtensions = map[string]string{
+	"bash":       "sh",
+	"c":          "c",
+	"c++":        "cpp",
+	"cpp":        "cpp",
+	"csharp":     "cs",
+	"css":        "css",
+	"diff":       "diff",
+	"dockerfile": "Dockerfile",
+	"go":         "go",
+	"golang":     "go",
+	"html":       "html",
+	"java":       "java",
+	"javascript": "js",
+	"js":         "js",
+	"json":       "json",
+	"jsx":        "jsx",
+	"kotlin":     "kt",
+	"markdown":   "md",
+	"md":         "md",
+	"patch":      "diff",
+	"php":        "php",
+	"py":         "py",
+	"python":     "py",
+	"rb":         "rb",
+	"ruby":       "rb",
+	"rust":       "rs",
+	"scala":      "scala",
+	"sh":         "sh",
+	"shell":      "sh",
+	"sql":        "sql",
+	"suggestion": "suggestion",
+	"swift":      "swift",
+	"toml":       "toml",
+	"ts":         "ts",
+	"tsx":        "tsx",
+	"typescript": "ts",
+	"yaml":       "yaml",
+	"yml":        "yaml",
+	"zsh":        "sh",
+}
+
+// parseCodeBlocks returns the fenced code blocks in a markdown body
+func parseCodeBlocks(body string) []CodeBlock {
+	var blocks []CodeBlock
+	var current *CodeBlock
+	var fence string
+	var code []string
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if current == nil {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				// The closing fence must use at least as many markers
+				fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+				info := strings.Fields(strings.TrimSpace(trimmed[len(fence):]))
+				current = &CodeBlock{}
+				if len(info) > 0 {
+					current.Language = strings.ToLower(info[0])
+				}
+				code = nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, fence) && strings.TrimLeft(trimmed, fence[:1]) == "" {
+			current.Code = strings.Join(code, "\n") + "\n"
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		code = append(code, line)
+	}
+
+	return blocks
+}
+
+// extractCode writes every fenced code block in the PR's comments to dir,
+// naming each file by comment ID and language, along with an index.json
+// describing where each block came from.
+func extractCode(feedback *PRFeedback, dir string) (int, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	comments := append(append([]ReviewComment{}, feedback.Comments...), feedback.GeneralIssues...)
+	var index []CodeBlock
+	for _, comment := range comments {
+		for i, block := range parseCodeBlocks(comment.Body) {
+			ext, ok := languageExtensions[block.Language]
+			if !ok {
+				ext = "txt"
+			}
+
+			block.CommentID = comment.ID
+			block.Author = comment.Author
+			block.Path = comment.Path
+			block.Line = comment.Line
+			block.File = fmt.Sprintf("%d-%d.%s", comment.ID, i+1, ext)
+
+			if err := os.WriteFile(filepath.Join(dir, block.File), []byte(block.Code), 0o644); err != nil {
+				return 0, fmt.Errorf("failed to write %s: %w", block.File, err)
+			}
+			index = append(index, block)
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), data, 0o644); err != nil {
+		return 0, fmt.Errorf("failed to write index.json: %w", err)
+	}
+
+	return len(index), nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FollowupRule classifies comments as needs-followup under a label for
+// `export --by-label`. The first matching rule wins.
+type FollowupRule struct {
+	Label        string `yaml:"label"`
+	CommentMatch `yaml:",inline"`
+}
+
+// defaultFollowupRules apply when the repository config doesn't declare any,
+// catching the usual ways feedback gets deferred
+var defaultFollowupRules = []FollowupRule{
+	{Label: "follow-up", CommentMatch: CommentMatch{Match: `(?i)\b(follow[- ]?up|later|todo|separate (pr|change)|out of scope|future pr|tech debt)\b`}},
+}
+
+// runLabelExport writes the PR's follow-up-worthy comments as one Markdown
+// document per label, for sprint planning once a PR merges with deferred
+// feedback. Resolved threads are included, since deferring often means
+// resolving with a promise to come back.
+func runLabelExport(args []string, output string) {
+	opts := parseArgs(args)
+	opts.includeResolved = true
+	client := resolvePR(opts)
+
+	config, err := loadConfig(client, opts.repoName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	rules := config.Followup
+	if len(rules) == 0 {
+		rules = defaultFollowupRules
+	}
+
+	feedback, err := getPRComments(client, opts, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+	groups, err := classifyFollowups(feedback, rules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(groups) == 0 {
+		fmt.Fprintf(os.Stderr, "No follow-up feedback on PR #%d\n", opts.prNumber)
+		return
+	}
+
+	labels := make([]string, 0, len(groups))
+	for label := range groups {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	if output == "" {
+		for i, label := range labels {
+			if i > 0 {
+				fmt.Println()
+			}
+			writeFollowupMarkdown(os.Stdout, feedback, label, groups[label])
+		}
+		return
+	}
+
+	if err := os.MkdirAll(output, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, label := range labels {
+		path := filepath.Join(output, labelFilename(label)+".md")
+		file, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		writeFollowupMarkdown(file, feedback, label, groups[label])
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d comment(s) to %s\n", len(groups[label]), path)
+	}
+}
+
+// classifyFollowups groups the comments needing follow-up by the label of
+// the first rule they match. A thread matches when its first comment or any
+// reply does, so "will fix in a follow-up" replies are picked up too.
+func classifyFollowups(feedback *PRFeedback, rules []FollowupRule) (map[string][]ReviewComment, error) {
+	patterns := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		if rule.Match == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in follow-up rule %q: %w", rule.Label, err)
+		}
+		patterns[i] = pattern
+	}
+
+	groups := make(map[string][]ReviewComment)
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for _, comment := range comments {
+			for i, rule := range rules {
+				if !followupMatches(&rule.CommentMatch, patterns[i], comment) {
+					continue
+				}
+				label := rule.Label
+				if label == "" {
+					label = "follow-up"
+				}
+				groups[label] = append(groups[label], comment)
+				break
+			}
+		}
+	}
+	return groups, nil
+}
+
+func followupMatches(match *CommentMatch, pattern *regexp.Regexp, comment ReviewComment) bool {
+	if commentMatches(match, pattern, comment) {
+		return true
+	}
+	for _, reply := range comment.Replies {
+		candidate := comment
+		candidate.Body = reply.Body
+		if commentMatches(match, pattern, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeFollowupMarkdown(w io.Writer, feedback *PRFeedback, label string, comments []ReviewComment) {
+	fmt.Fprintf(w, "# Follow-ups: %s\n\n", label)
+	fmt.Fprintf(w, "From [%s #%d](%s)", feedback.Title, feedback.PRNumber, feedback.URL)
+	if feedback.State != "" {
+		fmt.Fprintf(w, " (%s)", feedback.State)
+	}
+	fmt.Fprintf(w, "\n\n")
+
+	for _, comment := range comments {
+		location := "General"
+		if comment.Path != "" {
+			location = comment.Path
+			if comment.Line != nil {
+				location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+			}
+		}
+		fmt.Fprintf(w, "- [ ] **%s**: %s (@%s", location, firstLine(comment.Body), comment.Author)
+		if comment.DiscussionURL != "" {
+			fmt.Fprintf(w, ", [thread](%s)", comment.DiscussionURL)
+		}
+		fmt.Fprintf(w, ")\n")
+		for _, line := range strings.Split(strings.TrimSpace(comment.Body), "\n") {
+			fmt.Fprintf(w, "  > %s\n", line)
+		}
+		for _, reply := range comment.Replies {
+			fmt.Fprintf(w, "  >\n  > **@%s**: %s\n", reply.Author, firstLine(reply.Body))
+		}
+	}
+}
+
+// labelFilename turns a label into a safe file name, e.g. "Tech debt" into
+// "tech-debt"
+func labelFilename(label string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(label) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		return "follow-up"
+	}
+	return name
+}
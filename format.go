@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// printSummary prints a one-screen overview of the PR's feedback and score
+func printSummary(feedback *PRFeedback) {
+	score := feedback.Score
+	fmt.Printf("%s%s #%d%s\n", colorBold, feedback.Title, feedback.PRNumber, colorReset)
+	fmt.Printf("%s • %s\n\n", formatPRState(feedback.State), colorGray+feedback.URL+colorReset)
+
+	scoreColor := colorGreen
+	if score.Blocking > 0 || score.FailingRequiredChecks > 0 {
+		scoreColor = colorRed
+	} else if score.Total > 0 {
+		scoreColor = colorYellow
+	}
+	fmt.Printf("Score:    %s%s%d%s\n", colorBold, scoreColor, score.Total, colorReset)
+	fmt.Printf("Comments: %d blocking, %d normal, %d nit(s)\n", score.Blocking, score.Normal, score.Nits)
+
+	optional := len(feedback.StatusChecks) - score.FailingRequiredChecks
+	fmt.Printf("Checks:   %d failing required, %d failing optional\n", score.FailingRequiredChecks, optional)
+}
+
+// printPrompt renders the feedback as plain text instructions for an AI
+// coding assistant, most urgent items first.
+func printPrompt(feedback *PRFeedback) {
+	writePrompt(os.Stdout, feedback)
+}
+
+func writePrompt(w io.Writer, feedback *PRFeedback) {
+	score := feedback.Score
+	fmt.Fprintf(w, "Address the outstanding review feedback on PR #%d %q", feedback.PRNumber, feedback.Title)
+	if feedback.Repo != "" {
+		fmt.Fprintf(w, " in %s", feedback.Repo)
+	}
+	fmt.Fprintf(w, ".\n")
+	if feedback.ReviewDecision != "" {
+		fmt.Fprintf(w, "Review decision: %s\n", strings.ToLower(strings.ReplaceAll(feedback.ReviewDecision, "_", " ")))
+	}
+	fmt.Fprintf(w, "Feedback score: %d (%d blocking, %d normal, %d nits, %d failing required checks)\n",
+		score.Total, score.Blocking, score.Normal, score.Nits, score.FailingRequiredChecks)
+	if feedback.Summary != "" {
+		fmt.Fprintf(w, "\nSummary:\n%s\n", feedback.Summary)
+	}
+
+	var items []ReviewComment
+	for _, severity := range []string{severityBlocking, severityNormal, severityNit} {
+		for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+			for _, comment := range comments {
+				if comment.Severity == severity {
+					items = append(items, comment)
+				}
+			}
+		}
+	}
+
+	if len(items) > 0 {
+		fmt.Fprintf(w, "\nReview comments (blocking first):\n")
+	}
+	for i, comment := range items {
+		location := "general"
+		if comment.Path != "" {
+			location = comment.Path
+			if comment.Line != nil {
+				location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+			}
+			if comment.Outdated {
+				location += " (outdated)"
+			}
+		}
+		endorsed := ""
+		if comment.Endorsements > 0 {
+			endorsed = fmt.Sprintf(", endorsed by %d reviewer(s)", comment.Endorsements)
+		}
+		fmt.Fprintf(w, "\n%d. [%s] %s, from @%s (comment %d%s)\n", i+1, comment.Severity, location, comment.Author, comment.ID, endorsed)
+		for _, line := range strings.Split(strings.TrimSpace(comment.Body), "\n") {
+			fmt.Fprintf(w, "   %s\n", line)
+		}
+		for _, reply := range comment.Replies {
+			fmt.Fprintf(w, "   Reply from @%s:\n", reply.Author)
+			for _, line := range strings.Split(strings.TrimSpace(reply.Body), "\n") {
+				fmt.Fprintf(w, "     %s\n", line)
+			}
+		}
+		if comment.Note != "" {
+			fmt.Fprintf(w, "   Author's note: %s\n", comment.Note)
+		}
+		if comment.AssignedTo != "" {
+			fmt.Fprintf(w, "   Assigned to: @%s\n", comment.AssignedTo)
+		}
+		for _, doc := range comment.Docs {
+			fmt.Fprintf(w, "   Relevant docs: %s\n", strings.TrimSpace(doc.Title+" "+doc.URL))
+		}
+	}
+
+	if len(feedback.StatusChecks) > 0 {
+		fmt.Fprintf(w, "\nFailing checks:\n")
+	}
+	for _, check := range feedback.StatusChecks {
+		required := ""
+		if check.Required {
+			required = ", required"
+		}
+		fmt.Fprintf(w, "- %s (%s%s)", check.Name, strings.ToLower(check.Conclusion), required)
+		if check.CheckCommand != "" {
+			fmt.Fprintf(w, ": inspect with `%s`", check.CheckCommand)
+		} else if check.DetailsURL != "" {
+			fmt.Fprintf(w, ": %s", check.DetailsURL)
+		}
+		fmt.Fprintln(w)
+		for _, annotation := range check.Annotations {
+			fmt.Fprintf(w, "  - %s:%d (%s): %s\n", annotation.Path, annotation.StartLine, annotation.Level, firstLine(annotation.Message))
+		}
+	}
+	if len(feedback.SlowChecks) > 0 {
+		fmt.Fprintf(w, "\nChecks much slower than usual on the default branch:\n")
+	}
+	for _, check := range feedback.SlowChecks {
+		took := time.Duration(check.Seconds) * time.Second
+		usual := time.Duration(check.UsualSeconds) * time.Second
+		fmt.Fprintf(w, "- %s took %s, usually %s\n", check.Name, formatDuration(took), formatDuration(usual))
+	}
+
+	if len(items) == 0 && len(feedback.StatusChecks) == 0 {
+		fmt.Fprintf(w, "\nThere is no outstanding feedback.\n")
+	}
+}
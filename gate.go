@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// GateResult is the outcome of evaluating a single gate rule
+type GateResult struct {
+	Name    string   `json:"name"`
+	Passed  bool     `json:"passed"`
+	Count   int      `json:"count"`
+	Max     int      `json:"max"`
+	Matches []string `json:"matches,omitempty"`
+}
+
+// defaultGateRules apply when the repository config doesn't declare any
+var defaultGateRules = []GateRule{
+	{Name: "no unresolved comments", Comments: &CommentMatch{}},
+	{Name: "no failing checks", Checks: &CheckMatch{}},
+}
+
+// runGate evaluates the repository's review policy against the PR and exits
+// non-zero when any rule fails.
+func runGate(args []string) {
+	opts := parseArgs(args)
+	client := resolvePR(opts)
+
+	config, err := loadConfig(client, opts.repoName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	feedback, err := getPRFeedback(client, opts, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+
+	rules := config.Gate
+	if len(rules) == 0 {
+		rules = defaultGateRules
+	}
+
+	passed := true
+	var results []GateResult
+	for _, rule := range rules {
+		result, err := evaluateGateRule(rule, feedback)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error in gate rule %q: %v\n", rule.Name, err)
+			os.Exit(1)
+		}
+		passed = passed && result.Passed
+		results = append(results, result)
+	}
+
+	if opts.jsonOutput {
+		printJSON(opts, results)
+	} else {
+		printGateResults(feedback, results)
+	}
+
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+func evaluateGateRule(rule GateRule, feedback *PRFeedback) (GateResult, error) {
+	result := GateResult{Name: rule.Name, Max: rule.Max}
+
+	if rule.Comments != nil {
+		var pattern *regexp.Regexp
+		if rule.Comments.Match != "" {
+			var err error
+			pattern, err = regexp.Compile(rule.Comments.Match)
+			if err != nil {
+				return result, fmt.Errorf("invalid match pattern: %w", err)
+			}
+		}
+
+		comments := append(append([]ReviewComment{}, feedback.Comments...), feedback.GeneralIssues...)
+		for _, comment := range comments {
+			if comment.State == "resolved" || comment.Minimized || comment.AddressedIn != "" || comment.ReviewState == "APPROVED" || !commentMatches(rule.Comments, pattern, comment) {
+				continue
+			}
+			location := comment.Author
+			if comment.Path != "" {
+				location += ": " + comment.Path
+			}
+			result.Matches = append(result.Matches, location)
+		}
+	}
+
+	if rule.Checks != nil {
+		for _, check := range feedback.StatusChecks {
+			if !checkMatches(rule.Checks, check) {
+				continue
+			}
+			result.Matches = append(result.Matches, check.Name)
+		}
+	}
+
+	result.Count = len(result.Matches)
+	result.Passed = result.Count <= rule.Max
+	return result, nil
+}
+
+func commentMatches(match *CommentMatch, pattern *regexp.Regexp, comment ReviewComment) bool {
+	if len(match.Authors) > 0 && !containsFold(match.Authors, comment.Author) {
+		return false
+	}
+	if len(match.Paths) > 0 {
+		found := false
+		for _, glob := range match.Paths {
+			if comment.Path != "" && globMatch(glob, comment.Path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if pattern != nil && !pattern.MatchString(comment.Body) {
+		return false
+	}
+	if len(match.Labels) > 0 {
+		found := false
+		for _, label := range commentLabels(comment.Body) {
+			if containsFold(match.Labels, label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// commentTag matches a bracketed tag such as [security] or [blocking]
+var commentTag = regexp.MustCompile(`\[([a-z][a-z0-9-]*)\]`)
+
+// commentLabels returns the labels a comment carries: its conventional
+// comment label and decorations, and any bracketed tags
+func commentLabels(body string) []string {
+	text := strings.ToLower(strings.TrimSpace(body))
+	var labels []string
+	if match := conventionalLabel.FindStringSubmatch(text); match != nil {
+		labels = append(labels, match[1])
+		for _, decoration := range strings.Split(match[2], ",") {
+			if decoration = strings.TrimSpace(decoration); decoration != "" {
+				labels = append(labels, decoration)
+			}
+		}
+	}
+	for _, match := range commentTag.FindAllStringSubmatch(text, -1) {
+		labels = append(labels, match[1])
+	}
+	return labels
+}
+
+func checkMatches(match *CheckMatch, check StatusCheck) bool {
+	if len(match.Names) > 0 && !containsFold(match.Names, check.Name) {
+		return false
+	}
+	if len(match.Workflows) > 0 && !containsFold(match.Workflows, check.WorkflowName) {
+		return false
+	}
+	if match.Required != nil && check.Required != *match.Required {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func printGateResults(feedback *PRFeedback, results []GateResult) {
+	fmt.Printf("%s%s #%d%s\n\n", colorBold, feedback.Title, feedback.PRNumber, colorReset)
+
+	failed := 0
+	for _, result := range results {
+		if result.Passed {
+			fmt.Printf("%s✓%s %s\n", colorGreen, colorReset, result.Name)
+			continue
+		}
+		failed++
+		fmt.Printf("%s✗%s %s %s(%d found, %d allowed)%s\n", colorRed, colorReset, result.Name, colorGray, result.Count, result.Max, colorReset)
+		for _, match := range result.Matches {
+			fmt.Printf("    %s%s%s\n", colorGray, match, colorReset)
+		}
+	}
+
+	fmt.Println()
+	if failed == 0 {
+		fmt.Printf("%sGate passed%s\n", colorGreen, colorReset)
+	} else {
+		fmt.Printf("%sGate failed:%s %d of %d rule(s) not satisfied\n", colorRed, colorReset, failed, len(results))
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// defaultVendoredPaths are treated as vendored unless the config sets its own
+// `vendored:` list
+var defaultVendoredPaths = []string{"vendor/**", "third_party/**", "node_modules/**"}
+
+// generatedMatcher decides which paths hold generated or vendored code,
+// whose review comments are rarely actionable
+type generatedMatcher struct {
+	// rules are applied in order with the last match winning, as in
+	// .gitattributes
+	rules []generatedRule
+}
+
+type generatedRule struct {
+	pattern string
+	set     bool
+}
+
+// loadGeneratedMatcher combines the linguist-generated and linguist-vendored
+// attributes from .gitattributes with the configured vendored paths.
+func loadGeneratedMatcher(client *api.RESTClient, repo string, config *Config) *generatedMatcher {
+	matcher := &generatedMatcher{}
+
+	vendored := config.Vendored
+	if vendored == nil {
+		vendored = defaultVendoredPaths
+	}
+	for _, pattern := range vendored {
+		matcher.rules = append(matcher.rules, generatedRule{pattern: pattern, set: true})
+	}
+
+	data, err := readLocalFile(".gitattributes")
+	if err == nil && data == nil && client != nil && repo != "" {
+		data, err = readRemoteFile(client, repo, ".gitattributes")
+	}
+	if err != nil && !budgetSkipped(err) {
+		warnf("%v", err)
+	}
+	matcher.rules = append(matcher.rules, parseLinguistAttributes(data)...)
+	return matcher
+}
+
+// parseLinguistAttributes returns the .gitattributes lines that set or unset
+// linguist-generated or linguist-vendored
+func parseLinguistAttributes(data []byte) []generatedRule {
+	var rules []generatedRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			name, value, _ := strings.Cut(attr, "=")
+			unset := strings.HasPrefix(name, "-") || strings.HasPrefix(name, "!") || value == "false"
+			name = strings.TrimLeft(name, "-!")
+			if name != "linguist-generated" && name != "linguist-vendored" {
+				continue
+			}
+			rules = append(rules, generatedRule{pattern: gitattributesGlob(fields[0]), set: !unset})
+		}
+	}
+	return rules
+}
+
+// gitattributesGlob converts a .gitattributes pattern to a globMatch pattern.
+// Patterns without a slash match the file name in any directory.
+func gitattributesGlob(pattern string) string {
+	if strings.HasPrefix(pattern, "/") {
+		return strings.TrimPrefix(pattern, "/")
+	}
+	if !strings.Contains(pattern, "/") {
+		return "**/" + pattern
+	}
+	return pattern
+}
+
+func (m *generatedMatcher) matches(path string) bool {
+	generated := false
+	for _, rule := range m.rules {
+		if globMatch(rule.pattern, path) {
+			generated = rule.set
+		}
+	}
+	return generated
+}
+
+// markGenerated flags line comments on generated and vendored files
+func markGenerated(feedback *PRFeedback, matcher *generatedMatcher) {
+	for i := range feedback.Comments {
+		if feedback.Comments[i].Path != "" && matcher.matches(feedback.Comments[i].Path) {
+			feedback.Comments[i].Generated = true
+		}
+	}
+}
+
+// splitGenerated separates comments on generated files from the rest
+func splitGenerated(comments []ReviewComment) ([]ReviewComment, []ReviewComment) {
+	var actionable, generated []ReviewComment
+	for _, comment := range comments {
+		if comment.Generated {
+			generated = append(generated, comment)
+		} else {
+			actionable = append(actionable, comment)
+		}
+	}
+	return actionable, generated
+}
+
+// printGeneratedSummary lists comments on generated files in one collapsed
+// line each
+func printGeneratedSummary(comments []ReviewComment, separator string) {
+	fmt.Println("\n" + separator + "\n")
+	fmt.Printf("%s%d comment(s) on generated or vendored files%s %s(--include-generated to expand)%s\n", colorBold, len(comments), colorReset, colorGray, colorReset)
+	for _, comment := range comments {
+		location := comment.Path
+		if comment.Line != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+		}
+		fmt.Printf("  %s%s%s %s%s: %s%s%s\n", colorBlue, location, colorReset, colorGray, comment.Author, firstLine(comment.Body), colorReset, formatThreadSize(comment))
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// gitNotesRef is the notes ref that review summaries are written to
+const gitNotesRef = "refs/notes/pr-feedback"
+
+// writeGitNotes attaches a summary of the PR's review feedback to its merge
+// commit, so the review context travels with the repository history.
+func writeGitNotes(feedback *PRFeedback) error {
+	if feedback.State != "merged" || feedback.MergeCommitSHA == "" {
+		return fmt.Errorf("PR #%d is not merged yet", feedback.PRNumber)
+	}
+
+	sha := feedback.MergeCommitSHA
+	if err := exec.Command("git", "cat-file", "-e", sha+"^{commit}").Run(); err != nil {
+		return fmt.Errorf("merge commit %s is not available locally; run git fetch first", sha)
+	}
+
+	cmd := exec.Command("git", "notes", "--ref", gitNotesRef, "add", "--force", "--file", "-", sha)
+	cmd.Stdin = strings.NewReader(formatGitNote(feedback))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git notes failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// formatGitNote renders the feedback as plain text suitable for a git note,
+// with resolved and unresolved threads listed separately
+func formatGitNote(feedback *PRFeedback) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PR #%d: %s\n%s\n", feedback.PRNumber, feedback.Title, feedback.URL)
+
+	if len(feedback.GeneralIssues) > 0 {
+		fmt.Fprintf(&b, "\nReview comments (%d):\n", len(feedback.GeneralIssues))
+		for _, comment := range feedback.GeneralIssues {
+			fmt.Fprintf(&b, "- %s: %s\n", comment.Author, firstLine(comment.Body))
+		}
+	}
+
+	var resolved, unresolved []ReviewComment
+	for _, comment := range feedback.Comments {
+		if comment.State == "resolved" {
+			resolved = append(resolved, comment)
+		} else {
+			unresolved = append(unresolved, comment)
+		}
+	}
+	writeGitNoteThreads(&b, "Resolved line comments", resolved)
+	writeGitNoteThreads(&b, "Unresolved line comments", unresolved)
+
+	if len(feedback.StatusChecks) > 0 {
+		fmt.Fprintf(&b, "\nFailing checks at merge (%d):\n", len(feedback.StatusChecks))
+		for _, check := range feedback.StatusChecks {
+			fmt.Fprintf(&b, "- %s (%s)\n", check.Name, strings.ToLower(check.Conclusion))
+		}
+	}
+
+	return b.String()
+}
+
+func writeGitNoteThreads(b *strings.Builder, heading string, comments []ReviewComment) {
+	if len(comments) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s (%d):\n", heading, len(comments))
+	for _, comment := range comments {
+		location := comment.Path
+		if comment.Line != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+		} else if comment.OriginalLine != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.OriginalLine)
+		}
+		fmt.Fprintf(b, "- %s on %s: %s\n", comment.Author, location, firstLine(comment.Body))
+	}
+}
+
+// firstLine returns the first non-empty line of a comment body
+func firstLine(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// compileGrep compiles a --grep pattern, case-insensitive unless
+// --grep-case is set
+func compileGrep(pattern string, matchCase bool) (*regexp.Regexp, error) {
+	if !matchCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --grep pattern: %w", err)
+	}
+	return re, nil
+}
+
+// filterGrep keeps only the threads and general comments whose body, or the
+// body of a reply, matches pattern
+func filterGrep(feedback *PRFeedback, pattern *regexp.Regexp) {
+	if pattern == nil {
+		return
+	}
+	keep := func(comments []ReviewComment) []ReviewComment {
+		kept := comments[:0]
+		for _, comment := range comments {
+			if grepMatches(pattern, comment) {
+				kept = append(kept, comment)
+			}
+		}
+		return kept
+	}
+	feedback.Comments = keep(feedback.Comments)
+	feedback.GeneralIssues = keep(feedback.GeneralIssues)
+}
+
+func grepMatches(pattern *regexp.Regexp, comment ReviewComment) bool {
+	if pattern.MatchString(comment.Body) {
+		return true
+	}
+	for _, reply := range comment.Replies {
+		if pattern.MatchString(reply.Body) {
+			return true
+		}
+	}
+	return false
+}
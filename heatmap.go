@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// heatmapWidth is the length of the longest bar in --heatmap
+const heatmapWidth = 24
+
+// heatmapBlocks are the partial blocks used to draw bar ends in eighths
+var heatmapBlocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// heatmapRow is one file in the --heatmap chart
+type heatmapRow struct {
+	path     string
+	comments int
+	changed  int
+	density  float64
+}
+
+// printHeatmap charts how many unresolved comments each file attracted
+// relative to the lines it changed, densest first
+func printHeatmap(feedback *PRFeedback, files []PRFile) {
+	counts := make(map[string]int)
+	for _, comment := range feedback.Comments {
+		if comment.Path != "" && comment.State != "resolved" {
+			counts[comment.Path]++
+		}
+	}
+
+	var rows []heatmapRow
+	for _, file := range files {
+		if counts[file.Filename] == 0 {
+			continue
+		}
+		changed := file.Additions + file.Deletions
+		rows = append(rows, heatmapRow{
+			path:     file.Filename,
+			comments: counts[file.Filename],
+			changed:  changed,
+			density:  float64(counts[file.Filename]) / float64(max(changed, 1)),
+		})
+		delete(counts, file.Filename)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].density != rows[j].density {
+			return rows[i].density > rows[j].density
+		}
+		return rows[i].path < rows[j].path
+	})
+
+	fmt.Printf("%s%s #%d%s\n", colorBold, feedback.Title, feedback.PRNumber, colorReset)
+	fmt.Printf("%sUnresolved comments per line changed, across %d changed file(s)%s\n\n", colorGray, len(files), colorReset)
+	if len(rows) == 0 && len(counts) == 0 {
+		fmt.Printf("%s✓%s No unresolved line comments\n", colorGreen, colorReset)
+		return
+	}
+
+	for _, row := range rows {
+		ratio := row.density / rows[0].density
+		color := colorGreen
+		if ratio > 0.66 {
+			color = colorRed
+		} else if ratio > 0.33 {
+			color = colorYellow
+		}
+		bar := heatmapBar(ratio)
+		padding := strings.Repeat(" ", heatmapWidth-len([]rune(bar)))
+		changed := fmt.Sprintf("%d lines", row.changed)
+		fmt.Printf("%s%s%s%s %3d %s%-12s%s %s\n", color, bar, colorReset, padding, row.comments, colorGray, changed, colorReset, row.path)
+	}
+
+	// Comments on files no longer in the diff have nothing to compare against
+	var gone []string
+	for path := range counts {
+		gone = append(gone, path)
+	}
+	sort.Strings(gone)
+	for _, path := range gone {
+		fmt.Printf("%s%s%s %3d %s%-12s%s %s\n", colorGray, strings.Repeat("·", heatmapWidth), colorReset, counts[path], colorGray, "not in diff", colorReset, path)
+	}
+}
+
+// heatmapBar draws a bar ratio of heatmapWidth long, in eighths of a cell
+func heatmapBar(ratio float64) string {
+	eighths := int(ratio*heatmapWidth*8 + 0.5)
+	if eighths < 1 {
+		eighths = 1
+	}
+	return strings.Repeat("█", eighths/8) + heatmapBlocks[eighths%8]
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// hookMarker identifies hooks written by install-hooks, so they can be
+// replaced without clobbering anyone else's
+const hookMarker = "# Installed by gh pr-feedback install-hooks"
+
+// prePushHook records a checkpoint in the background so pushing isn't held
+// up by the API. Git has no post-push hook; pre-push runs as the push starts,
+// which is the same boundary for reviewers.
+const prePushHook = `#!/bin/sh
+` + hookMarker + `
+gh pr-feedback checkpoint >/dev/null 2>&1 &
+exit 0
+`
+
+// pushCheckpoint marks the boundary between review rounds: the threads open
+// when the author last pushed. Threads opened afterwards are feedback on the
+// new changes.
+type pushCheckpoint struct {
+	At      string `json:"at"`
+	SHA     string `json:"sha,omitempty"`
+	Threads []int  `json:"threads,omitempty"`
+}
+
+// runInstallHooks installs the pre-push hook that records checkpoints
+func runInstallHooks(args []string) {
+	var force bool
+	for _, arg := range args {
+		switch arg {
+		case "--force":
+			force = true
+		default:
+			fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback install-hooks [--force]\n")
+			os.Exit(1)
+		}
+	}
+
+	// --git-path follows core.hooksPath and worktrees
+	output, err := exec.Command("git", "rev-parse", "--git-path", "hooks/pre-push").Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+		os.Exit(1)
+	}
+	path := strings.TrimSpace(string(output))
+	if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) && !force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists; add `gh pr-feedback checkpoint` to it, or use --force to replace it\n", path)
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(path, []byte(prePushHook), 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing hook: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s✓%s Installed %s; each push now marks the start of a review round\n", colorGreen, colorReset, path)
+}
+
+// runCheckpoint records the threads open on the current PR as of now, as a
+// push starts. It's run by the pre-push hook.
+func runCheckpoint(args []string) {
+	at := now().UTC().Format(time.RFC3339)
+	opts := parseArgs(args)
+	client := resolvePR(opts)
+
+	checkpoint := &pushCheckpoint{At: at}
+	if sha, err := exec.Command("git", "rev-parse", "HEAD").Output(); err == nil {
+		checkpoint.SHA = strings.TrimSpace(string(sha))
+	}
+	// Without the thread list the checkpoint still works by time alone
+	if feedback, err := getPRComments(client, opts, opts.repoName, opts.prNumber); err == nil {
+		for _, comments := range [][]ReviewComment{feedback.GeneralIssues, feedback.Comments} {
+			for _, comment := range comments {
+				checkpoint.Threads = append(checkpoint.Threads, comment.ID)
+			}
+		}
+	}
+
+	state, err := loadPRState(opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+		os.Exit(1)
+	}
+	state.Checkpoint = checkpoint
+	if err := savePRState(opts.prNumber, state); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving state: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// markSincePush flags the threads opened after the last push checkpoint
+func markSincePush(feedback *PRFeedback, checkpoint *pushCheckpoint) {
+	if checkpoint == nil {
+		return
+	}
+	at, err := parseTime(checkpoint.At)
+	if err != nil {
+		return
+	}
+	before := make(map[int]bool, len(checkpoint.Threads))
+	for _, id := range checkpoint.Threads {
+		before[id] = true
+	}
+	feedback.Checkpoint = checkpoint
+	for _, comments := range [][]ReviewComment{feedback.GeneralIssues, feedback.Comments} {
+		for i := range comments {
+			created, err := parseTime(comments[i].CreatedAt)
+			comments[i].SincePush = err == nil && created.After(at) && !before[comments[i].ID]
+		}
+	}
+}
+
+// printCheckpointSummary says how the threads split across the last push
+func printCheckpointSummary(feedback *PRFeedback) {
+	checkpoint := feedback.Checkpoint
+	if checkpoint == nil {
+		return
+	}
+	after, total := 0, len(feedback.GeneralIssues)+len(feedback.Comments)
+	for _, comments := range [][]ReviewComment{feedback.GeneralIssues, feedback.Comments} {
+		for _, comment := range comments {
+			if comment.SincePush {
+				after++
+			}
+		}
+	}
+	pushed := "Pushed"
+	if checkpoint.SHA != "" {
+		pushed += " " + shortSHA(checkpoint.SHA)
+	}
+	if t, err := parseTime(checkpoint.At); err == nil {
+		pushed += " " + formatTimeAgo(now().Sub(t))
+	}
+	fmt.Printf("%s↑%s %s: %d thread(s) opened since, %d before\n", colorCyan, colorReset, pushed, after, total-after)
+}
+
+// formatSincePush tags a thread opened after the last push
+func formatSincePush(comment ReviewComment) string {
+	if !comment.SincePush {
+		return ""
+	}
+	return fmt.Sprintf(" %s• New since push%s", colorCyan, colorReset)
+}
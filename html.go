@@ -0,0 +1,112 @@
+package main
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// htmlReport is a single-file HTML page: the styles are inline and nothing
+// is loaded from elsewhere, so it can be attached to a ticket or emailed
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lower":    strings.ToLower,
+	"decision": func(s string) string { return strings.ToLower(strings.ReplaceAll(s, "_", " ")) },
+	"diffLine": diffLineClass,
+	"lines":    func(s string) []string { return strings.Split(strings.TrimRight(s, "\n"), "\n") },
+	"location": func(comment ReviewComment) string {
+		if comment.Path == "" {
+			return "General comment"
+		}
+		if line := commentLine(comment); line > 0 {
+			return comment.Path + ":" + strconv.Itoa(line)
+		}
+		return comment.Path
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} #{{.PRNumber}}</title>
+<style>
+body { font: 14px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 960px; margin: 2em auto; padding: 0 1em; }
+a { color: #0969da; }
+h1 { font-size: 1.6em; margin-bottom: 0; }
+.meta { color: #59636e; }
+.badge { display: inline-block; padding: 0 .6em; border-radius: 1em; font-size: 12px; font-weight: 600; color: #fff; background: #59636e; }
+.badge.failure, .badge.error, .badge.required { background: #cf222e; }
+.badge.cancelled, .badge.timed_out, .badge.action_required, .badge.outdated { background: #9a6700; }
+.badge.resolved { background: #1a7f37; }
+ul.checks { list-style: none; padding: 0; }
+ul.checks li { margin: .3em 0; }
+details { border: 1px solid #d1d9e0; border-radius: 6px; margin: .8em 0; }
+summary { cursor: pointer; padding: .5em .8em; background: #f6f8fa; border-radius: 6px; }
+.thread { padding: 0 .8em .5em; }
+.comment { white-space: pre-wrap; margin: .6em 0; }
+.reply { border-left: 3px solid #d1d9e0; padding-left: .8em; margin-left: .4em; }
+.author { font-weight: 600; }
+pre.diff { font: 12px/1.4 ui-monospace, SFMono-Regular, Menlo, monospace; background: #f6f8fa; padding: .5em; overflow-x: auto; margin: .6em 0; }
+pre.diff span { display: block; }
+.add { background: #dafbe1; color: #116329; }
+.del { background: #ffebe9; color: #82071e; }
+.hunk { color: #0550ae; }
+</style>
+</head>
+<body>
+<h1>{{.Title}} <span class="meta">#{{.PRNumber}}</span></h1>
+<p class="meta"><a href="{{.URL}}">{{.URL}}</a>{{with .ReviewDecision}} &middot; {{decision .}}{{end}}</p>
+{{with .Summary}}<h2>Summary</h2>
+<p class="comment">{{.}}</p>
+{{end}}
+{{- if .StatusChecks}}<h2>Failing checks</h2>
+<ul class="checks">
+{{- range .StatusChecks}}
+<li><span class="badge {{lower .Conclusion}}">{{lower .Conclusion}}</span>{{if .Required}} <span class="badge required">required</span>{{end}}
+{{if .DetailsURL}}<a href="{{.DetailsURL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}
+{{- range .Annotations}}<br><code>{{.Path}}:{{.StartLine}}</code> {{.Message}}{{end}}</li>
+{{- end}}
+</ul>
+{{end}}
+{{- if or .GeneralIssues .Comments}}<h2>Review comments</h2>
+{{range .GeneralIssues}}{{template "thread" .}}{{end}}
+{{- range .Comments}}{{template "thread" .}}{{end}}
+{{- end}}
+</body>
+</html>
+{{define "thread"}}<details{{if ne .State "resolved"}} open{{end}}>
+<summary><span class="author">@{{.Author}}</span> on {{location .}}
+{{- if .Outdated}} <span class="badge outdated">outdated</span>{{end}}
+{{- if eq .State "resolved"}} <span class="badge resolved">resolved</span>{{end}}</summary>
+<div class="thread">
+{{- if .DiffHunk}}
+<pre class="diff">{{range lines .DiffHunk}}<span class="{{diffLine .}}">{{.}}</span>{{end}}</pre>
+{{- end}}
+<div class="comment">{{.Body}}</div>
+{{- if .HTMLURL}}<a href="{{.HTMLURL}}">View on GitHub</a>{{end}}
+{{- range .Replies}}
+<div class="reply"><span class="author">@{{.Author}}</span>
+<div class="comment">{{.Body}}</div></div>
+{{- end}}
+</div>
+</details>
+{{end}}`))
+
+// writeHTML writes the feedback as a self-contained HTML report, with a
+// collapsible section per thread (resolved ones start collapsed), colored
+// diff hunks and a badge for each check's conclusion
+func writeHTML(w io.Writer, feedback *PRFeedback) error {
+	return htmlReport.Execute(w, feedback)
+}
+
+// diffLineClass is the class a diff hunk line is highlighted with
+func diffLineClass(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+"):
+		return "add"
+	case strings.HasPrefix(line, "-"):
+		return "del"
+	case strings.HasPrefix(line, "@@"):
+		return "hunk"
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// whereKeys are the fields `ids --where` can filter on
+var whereKeys = []string{"author", "path", "severity", "state", "association", "outdated", "bot", "match"}
+
+// whereTerm is one `--where key=value` (or key!=value) filter
+type whereTerm struct {
+	key    string
+	value  string
+	negate bool
+	// pattern is the compiled value of a match term
+	pattern *regexp.Regexp
+}
+
+// parseWhere parses a --where term such as author=coderabbitai,
+// path!=docs/** or match=(?i)typo
+func parseWhere(value string) (whereTerm, error) {
+	key, rest, ok := strings.Cut(value, "=")
+	if !ok {
+		return whereTerm{}, fmt.Errorf("--where expects key=value, e.g. author=coderabbitai")
+	}
+	term := whereTerm{key: key, value: rest}
+	if strings.HasSuffix(key, "!") {
+		term.key, term.negate = strings.TrimSuffix(key, "!"), true
+	}
+	if !containsFold(whereKeys, term.key) {
+		return whereTerm{}, fmt.Errorf("unknown --where key %q (expected %s)", term.key, strings.Join(whereKeys, ", "))
+	}
+	term.key = strings.ToLower(term.key)
+	switch term.key {
+	case "match":
+		pattern, err := regexp.Compile(term.value)
+		if err != nil {
+			return whereTerm{}, fmt.Errorf("invalid --where match pattern: %w", err)
+		}
+		term.pattern = pattern
+	case "outdated", "bot":
+		if _, err := strconv.ParseBool(term.value); err != nil {
+			return whereTerm{}, fmt.Errorf("--where %s expects true or false", term.key)
+		}
+	}
+	return term, nil
+}
+
+// matches reports whether a comment satisfies the term
+func (t whereTerm) matches(comment ReviewComment) bool {
+	var matched bool
+	switch t.key {
+	case "author":
+		matched = strings.EqualFold(comment.Author, strings.TrimPrefix(t.value, "@"))
+	case "path":
+		matched = comment.Path != "" && globMatch(t.value, comment.Path)
+	case "severity":
+		matched = strings.EqualFold(comment.Severity, t.value)
+	case "state":
+		state := comment.State
+		if state == "" {
+			state = "unresolved"
+		}
+		matched = strings.EqualFold(state, t.value)
+	case "association":
+		matched = strings.EqualFold(comment.AuthorAssoc, t.value)
+	case "outdated":
+		want, _ := strconv.ParseBool(t.value)
+		matched = comment.Outdated == want
+	case "bot":
+		want, _ := strconv.ParseBool(t.value)
+		matched = commentIsBot(comment) == want
+	case "match":
+		matched = t.pattern.MatchString(comment.Body)
+	}
+	return matched != t.negate
+}
+
+// runIDs prints the IDs of the comments matching every --where term, one per
+// line, for piping into scripted bulk actions such as `xargs gh pr-feedback
+// resolve`. The usual filter flags apply too. With --threads the GraphQL IDs
+// of the matching review threads are printed instead, for scripts calling
+// the API directly.
+func runIDs(args []string) {
+	var terms []whereTerm
+	var threads bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--where":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback ids [--where key=value]... [--threads] [pr-number]\n")
+				os.Exit(1)
+			}
+			term, err := parseWhere(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			terms = append(terms, term)
+			i++
+		case "--threads":
+			threads = true
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+
+	opts := parseArgs(rest)
+	provider := selectProvider(opts)
+	resolveTarget(provider, opts)
+	feedback, _, err := fetchFeedback(provider, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+	filterFeedback(feedback, opts)
+
+	var threadIDs map[int]string
+	if threads {
+		if provider.Name() != "github" {
+			fmt.Fprintf(os.Stderr, "Error: --threads is only supported for GitHub\n")
+			os.Exit(1)
+		}
+		list, err := fetchReviewThreads(createGraphQLClient(), opts.repoName, opts.prNumber)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		threadIDs = make(map[int]string, len(list))
+		for _, thread := range list {
+			threadIDs[thread.Comment.ID] = thread.ID
+		}
+	}
+
+	for _, comments := range [][]ReviewComment{feedback.GeneralIssues, feedback.Comments} {
+		for _, comment := range comments {
+			matched := true
+			for _, term := range terms {
+				if !term.matches(comment) {
+					matched = false
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+			if !threads {
+				fmt.Println(comment.ID)
+			} else if id := threadIDs[comment.ID]; id != "" {
+				fmt.Println(id)
+			}
+		}
+	}
+}
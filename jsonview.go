@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// runJSONView renders feedback previously saved with --json, e.g. from CI,
+// through the human-readable output. Reads stdin when the file is "-".
+func runJSONView(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback json-view <file.json|->\n")
+		os.Exit(1)
+	}
+
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading snapshot: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Multi-PR output (--mine, --stack, --org) wraps a list of PRs
+	var probe struct {
+		PullRequests json.RawMessage `json:"pull_requests"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing snapshot: %v\n", err)
+		os.Exit(1)
+	}
+	if probe.PullRequests != nil {
+		var result MultiFeedback
+		if err := json.Unmarshal(data, &result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		for _, feedback := range result.PullRequests {
+			if feedback.Score == nil {
+				feedback.Score = scoreFeedback(feedback, defaultScoreWeights)
+			}
+		}
+		printMultiFeedback(&result)
+		return
+	}
+
+	var feedback PRFeedback
+	if err := json.Unmarshal(data, &feedback); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing snapshot: %v\n", err)
+		os.Exit(1)
+	}
+	if feedback.PRNumber == 0 {
+		fmt.Fprintf(os.Stderr, "Error: %s doesn't look like gh pr-feedback --json output\n", args[0])
+		os.Exit(1)
+	}
+	printHumanReadable(&options{}, &feedback)
+}
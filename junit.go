@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Time      string       `xml:"time,attr"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the failing checks as a JUnit XML report, a test suite
+// per workflow with a failed test case per check, so CI dashboards and test
+// explorers can show them. Annotations are included in each failure.
+func writeJUnit(w io.Writer, feedback *PRFeedback) error {
+	report := junitTestSuites{Name: fmt.Sprintf("%s #%d", feedback.Title, feedback.PRNumber)}
+	suites := make(map[string]*junitTestSuite)
+	durations := make(map[string]float64)
+	var total float64
+	for _, check := range feedback.StatusChecks {
+		workflow := check.WorkflowName
+		if workflow == "" {
+			workflow = "checks"
+		}
+		suite, ok := suites[workflow]
+		if !ok {
+			suite = &junitTestSuite{Name: workflow}
+			suites[workflow] = suite
+		}
+
+		seconds := 0.0
+		start, err1 := parseTime(check.StartedAt)
+		end, err2 := parseTime(check.CompletedAt)
+		if err1 == nil && err2 == nil && end.After(start) {
+			seconds = end.Sub(start).Seconds()
+		}
+
+		var details strings.Builder
+		if check.DetailsURL != "" {
+			fmt.Fprintf(&details, "%s\n", check.DetailsURL)
+		}
+		for _, annotation := range check.Annotations {
+			fmt.Fprintf(&details, "%s:%d: %s\n", annotation.Path, annotation.StartLine, annotation.Message)
+		}
+		conclusion := strings.ToLower(check.Conclusion)
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      check.Name,
+			ClassName: workflow,
+			Time:      fmt.Sprintf("%.3f", seconds),
+			Failure:   junitFailure{Message: fmt.Sprintf("%s %s", check.Name, conclusion), Type: conclusion, Text: details.String()},
+		})
+		suite.Tests++
+		suite.Failures++
+		durations[workflow] += seconds
+		total += seconds
+	}
+
+	names := make([]string, 0, len(suites))
+	for name := range suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		suites[name].Time = fmt.Sprintf("%.3f", durations[name])
+		report.Suites = append(report.Suites, *suites[name])
+		report.Tests += suites[name].Tests
+		report.Failures += suites[name].Failures
+	}
+	report.Time = fmt.Sprintf("%.3f", total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
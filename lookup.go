@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// commitSHA matches arguments that could be a full or abbreviated commit SHA
+var commitSHA = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// findPRForRef returns the PR for a branch name or commit SHA, like
+// `gh pr view <branch>`. Open PRs are preferred, and branches in forks can be
+// given as owner:branch.
+func findPRForRef(client *api.RESTClient, repo, ref string) (int, error) {
+	type pull struct {
+		Number int    `json:"number"`
+		State  string `json:"state"`
+	}
+
+	// A hex branch name is possible, so fall back to branches when no commit
+	// by that SHA has a PR
+	if commitSHA.MatchString(ref) {
+		var pulls []pull
+		if err := client.Get(fmt.Sprintf("repos/%s/commits/%s/pulls", repo, ref), &pulls); err == nil && len(pulls) > 0 {
+			for _, pull := range pulls {
+				if pull.State == "open" {
+					return pull.Number, nil
+				}
+			}
+			return pulls[0].Number, nil
+		}
+	}
+
+	head := ref
+	if !strings.Contains(head, ":") {
+		owner, _, _ := strings.Cut(repo, "/")
+		head = owner + ":" + ref
+	}
+	var pulls []pull
+	endpoint := fmt.Sprintf("repos/%s/pulls?state=all&head=%s", repo, url.QueryEscape(head))
+	if err := client.Get(endpoint, &pulls); err != nil {
+		return 0, fmt.Errorf("failed to look up PR for %s: %w", ref, err)
+	}
+	if len(pulls) == 0 {
+		return 0, fmt.Errorf("no PR found for branch or commit %q in %s", ref, repo)
+	}
+	for _, pull := range pulls {
+		if pull.State == "open" {
+			return pull.Number, nil
+		}
+	}
+	return pulls[0].Number, nil
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"net/http"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -12,8 +15,8 @@ import (
 	"github.com/cli/go-gh/v2/pkg/api"
 )
 
-// ANSI color codes
-const (
+// ANSI color codes, cleared by disableColors when the terminal can't show them
+var (
 	colorReset  = "\033[0m"
 	colorRed    = "\033[31m"
 	colorGreen  = "\033[32m"
@@ -33,15 +36,59 @@ type ReviewComment struct {
 	Line            *int   `json:"line"`
 	StartLine       *int   `json:"start_line"`
 	OriginalLine    *int   `json:"original_line,omitempty"`
+	Side            string `json:"side,omitempty"`
 	DiffHunk        string `json:"diff_hunk,omitempty"`
 	Author          string `json:"author"`
 	AuthorAssoc     string `json:"author_association,omitempty"`
+	// AuthorIsBot is set when GitHub reports the author as a bot account
+	AuthorIsBot     bool   `json:"author_is_bot,omitempty"`
 	State           string `json:"state"`
 	InReplyTo       *int   `json:"in_reply_to_id"`
 	CreatedAt       string `json:"created_at"`
 	UpdatedAt       string `json:"updated_at"`
 	Outdated        bool   `json:"outdated,omitempty"`
 	SubjectType     string `json:"subject_type,omitempty"`
+	PositionState   string `json:"position_state,omitempty"`
+	Note            string `json:"note,omitempty"`
+	// AssignedTo is the collaborator the thread was handed to with `assign`
+	AssignedTo      string `json:"assigned_to,omitempty"`
+	Severity        string `json:"severity,omitempty"`
+	Redacted        bool   `json:"redacted,omitempty"`
+	Annotations     []Annotation `json:"annotations,omitempty"`
+	DiscussionURL   string `json:"discussion_url,omitempty"`
+	Generated       bool   `json:"generated,omitempty"`
+	PreviousBody    string `json:"previous_body,omitempty"`
+	CommitID        string `json:"commit_id,omitempty"`
+	// Endorsements counts 👍 reactions, a sign other reviewers agree
+	Endorsements    int    `json:"endorsements,omitempty"`
+	// Reactions counts each kind of reaction, keyed by REST name, e.g. eyes
+	Reactions       map[string]int `json:"reactions,omitempty"`
+	// ReviewState is set on review summaries, e.g. CHANGES_REQUESTED
+	ReviewState     string `json:"review_state,omitempty"`
+	// Summary is set when a renderer condensed a bot's comment, and is
+	// shown instead of the body
+	Summary         string `json:"summary,omitempty"`
+	// HTMLURL is the comment's own permalink on GitHub
+	HTMLURL         string `json:"html_url,omitempty"`
+	// AddressedIn is the commit whose message references the thread
+	AddressedIn     string `json:"addressed_in,omitempty"`
+	// SincePush is set on threads opened after the last push checkpoint
+	SincePush       bool   `json:"since_push,omitempty"`
+	// Docs are the configured docs relevant to the file commented on
+	Docs            []DocLink `json:"docs,omitempty"`
+	// NodeID is the comment's GraphQL ID
+	NodeID          string `json:"node_id,omitempty"`
+	// Minimized is set on comments hidden on GitHub, shown with
+	// --show-minimized, e.g. as "off-topic" or "outdated"
+	Minimized       bool   `json:"minimized,omitempty"`
+	MinimizedReason string `json:"minimized_reason,omitempty"`
+	// Replies are the rest of the thread, oldest first
+	Replies         []ReviewComment `json:"replies,omitempty"`
+	// ReplyCount, WordCount and ReadingSeconds size up the whole thread,
+	// and are kept when --no-replies drops the replies themselves
+	ReplyCount      int    `json:"reply_count"`
+	WordCount       int    `json:"word_count"`
+	ReadingSeconds  int    `json:"reading_seconds"`
 }
 
 type StatusCheck struct {
@@ -49,52 +96,1135 @@ type StatusCheck struct {
 	Status       string `json:"status"`
 	Conclusion   string `json:"conclusion"`
 	DetailsURL   string `json:"details_url"`
+	// Provider is the CI system the check ran on, e.g. github-actions,
+	// buildkite or custom
+	Provider     string `json:"provider,omitempty"`
 	WorkflowName string `json:"workflow_name,omitempty"`
 	RunID        string `json:"run_id,omitempty"`
 	StartedAt    string `json:"started_at"`
 	CompletedAt  string `json:"completed_at"`
 	CheckCommand string `json:"check_command,omitempty"`
+	Required     bool   `json:"required,omitempty"`
+	Artifacts    []Artifact `json:"artifacts,omitempty"`
+	Annotations  []CheckAnnotation `json:"annotations,omitempty"`
+	// Owners own the files the check flagged, when that isn't you
+	Owners       []string `json:"owners,omitempty"`
 }
 
 type PRFeedback struct {
+	Repo          string          `json:"repo,omitempty"`
 	PRNumber      int             `json:"pr_number"`
 	Title         string          `json:"title"`
+	// Author opened the PR
+	Author        string          `json:"author,omitempty"`
 	URL           string          `json:"url"`
+	State         string          `json:"state,omitempty"`
+	MergeCommitSHA string         `json:"merge_commit_sha,omitempty"`
+	BaseRef       string          `json:"base_ref,omitempty"`
+	// Pending holds the comments of your own unsubmitted review, which
+	// nobody else can see yet
+	Pending       []ReviewComment `json:"pending,omitempty"`
 	Comments      []ReviewComment `json:"comments"`
 	GeneralIssues []ReviewComment `json:"general_issues"`
 	StatusChecks  []StatusCheck   `json:"status_checks"`
+	// SlowChecks took much longer than usual, whether or not they failed
+	SlowChecks    []SlowCheck     `json:"slow_checks,omitempty"`
+	Topics        []Topic         `json:"topics,omitempty"`
+	// Summary is the overview generated by --summarize
+	Summary       string          `json:"summary,omitempty"`
+	// Checkpoint is the last push recorded by the install-hooks hook
+	Checkpoint    *pushCheckpoint `json:"checkpoint,omitempty"`
+	Score         *FeedbackScore  `json:"score,omitempty"`
+	Counts        *FeedbackCounts `json:"counts,omitempty"`
+	ReviewRequests []ReviewRequest `json:"review_requests,omitempty"`
+	// ReviewDecision is GitHub's overall verdict, e.g. CHANGES_REQUESTED
+	ReviewDecision string         `json:"review_decision,omitempty"`
+	Reviews       []Review        `json:"reviews,omitempty"`
+	// jobs are the PR's finished Actions jobs from the status check rollup,
+	// kept for --slow-checks
+	jobs          []prCheck
 }
 
-func main() {
-	var jsonOutput bool
-	var targetDir string
-	var prNumber int
-	var repoName string
+// options holds the flags and positional arguments shared by every command
+type options struct {
+	jsonOutput bool
+	resume     bool
+	queue      bool
+	watch      bool
+	mine       bool
+	stack      bool
+	org        string
+	reviewLoad bool
+	// only is "comments" for --comments-only or "checks" for --checks-only
+	only       string
+	// outdated is "hide" for --hide-outdated or "only" for --only-outdated.
+	// By default outdated threads are collapsed into a section after the rest.
+	outdated   string
+	includeResolved  bool
+	includeAddressed bool
+	showMinimized    bool
+	// strict makes a failed review thread lookup fatal instead of falling
+	// back to REST
+	strict     bool
+	noWarnings bool
+	gitNotes   bool
+	extractDir string
+	provider   string
+	topics     bool
+	topicsCmd  string
+	summarize  bool
+	minimal    bool
+	summarizeCmd string
+	summary    bool
+	compact    bool
+	indent     string
+	retention  time.Duration
+	analyzers  []string
+	artifactsDir string
+	shallow    bool
+	slowChecks bool
+	outputs    []outputFile
+	caBundle   string
+	noReplies  bool
+	unanswered bool
+	heatmap    bool
+	base       string
+	estimate   bool
+	routeFailures string
+	record     string
+	replay     string
+	authors    []string
+	excludeAuthors []string
+	associations []string
+	// bots is "exclude" for --no-bots or "only" for --bots-only
+	bots       string
+	paths      []string
+	// scope is the directory, relative to the repository root, that line
+	// comments and check annotations are limited to
+	scope      string
+	scopeSet   bool
+	since      time.Time
+	grep       *regexp.Regexp
+	grepPattern string
+	grepCase   bool
+	minReactions int
+	sort       string
+	limit      int
+	reaction   string
+	// ref is a branch name or commit SHA to find the PR by
+	ref        string
+	includeGenerated bool
+	commits    string
+	maxRequests int
+	editPlan   bool
+	editor     string
+	format     string
+	// template is a text/template rendered with the feedback, for --template
+	template   string
+	targetDir  string
+	prNumber   int
+	repoName   string
+}
 
-	// Parse arguments
+func main() {
 	args := os.Args[1:]
+	setupColors()
+
+	// Dispatch subcommands before parsing the default command's flags
+	if len(args) > 0 {
+		switch args[0] {
+		case "checks":
+			runChecks(args[1:])
+			return
+		case "context":
+			runContext(args[1:])
+			return
+		case "doctor":
+			runDoctor(args[1:])
+			return
+		case "diff-comments":
+			runDiffComments(args[1:])
+			return
+		case "ack":
+			runAck(args[1:])
+			return
+		case "assign":
+			runAssign(args[1:])
+			return
+		case "note":
+			runNote(args[1:])
+			return
+		case "export":
+			runExport(args[1:])
+			return
+		case "gate":
+			runGate(args[1:])
+			return
+		case "ids":
+			runIDs(args[1:])
+			return
+		case "install-hooks":
+			runInstallHooks(args[1:])
+			return
+		case "checkpoint":
+			runCheckpoint(args[1:])
+			return
+		case "ping":
+			runPing(args[1:])
+			return
+		case "reply":
+			runReply(args[1:])
+			return
+		case "resolve":
+			runResolve(args[1:])
+			return
+		case "review":
+			runReview(args[1:])
+			return
+		case "revisit":
+			runRevisit(args[1:])
+			return
+		case "json-view":
+			runJSONView(args[1:])
+			return
+		case "triage":
+			runTriage(args[1:])
+			return
+		case "todo":
+			runTodo(args[1:])
+			return
+		case "tui":
+			runTUI(args[1:])
+			return
+		}
+	}
+
+	opts := parseArgs(args)
+	defer budget.report()
+	if opts.reviewLoad {
+		if opts.org == "" {
+			fmt.Fprintf(os.Stderr, "Error: --review-load requires --org\n")
+			os.Exit(1)
+		}
+		runReviewLoad(opts)
+		return
+	}
+	if opts.mine || opts.stack || opts.org != "" || opts.base != "" {
+		if len(opts.outputs) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: --out isn't supported with --mine, --stack, --org or --base\n")
+			os.Exit(1)
+		}
+		runMultiPR(opts)
+		return
+	}
+	provider := selectProvider(opts)
+	resolveScope(opts)
+	resolveTarget(provider, opts)
+
+	// The note records the whole review, including what was resolved
+	if opts.gitNotes {
+		opts.includeResolved = true
+	}
+
+	if opts.shallow {
+		if provider.Name() != "github" {
+			fmt.Fprintf(os.Stderr, "Error: --shallow is only supported for GitHub\n")
+			os.Exit(1)
+		}
+		if opts.unanswered {
+			fmt.Fprintf(os.Stderr, "Error: --unanswered needs every reply, which --shallow doesn't fetch\n")
+			os.Exit(1)
+		}
+	}
+
+	// Keep polling and report what changes instead of printing once
+	if opts.watch {
+		runWatch(provider, opts)
+		return
+	}
+	// Stream items out as they're fetched rather than after everything
+	if opts.format == "ndjson" {
+		runNDJSON(provider, opts)
+		return
+	}
+
+	// Fetch PR details and review comments
+	feedback, client, err := fetchFeedback(provider, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+
+	filterFeedback(feedback, opts)
+
+	// Feedback other reviewers agree with is more likely to need acting on
+	if opts.sort != "" {
+		sortFeedback(feedback, opts.sort)
+	} else {
+		sortByEndorsements(feedback)
+	}
+
+	// In PRs shared by several authors, each may only want the feedback on
+	// their own commits
+	if opts.commits != "" {
+		if provider.Name() != "github" {
+			fmt.Fprintf(os.Stderr, "Error: --commits is only supported for GitHub\n")
+			os.Exit(1)
+		}
+		rangeClient := client
+		if rangeClient == nil {
+			rangeClient = createClient()
+		}
+		commits, err := fetchCommitRange(rangeClient, opts.repoName, opts.commits)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		filterByCommits(feedback, commits)
+	}
+
+	// Rank how much attention the PR needs, using the repo's weights if set.
+	// Shallow runs skip the remote config lookup to stay fast.
+	config, err := loadConfig(client, opts.repoName)
+	if err != nil {
+		if !budgetSkipped(err) {
+			warnf("%v", err)
+		}
+		config = &Config{Score: defaultScoreWeights}
+	}
+
+	// Feedback on generated and vendored files is rarely actionable, so it's
+	// left out of the score and collapsed in the output
+	if !opts.includeGenerated {
+		markGenerated(feedback, loadGeneratedMatcher(client, opts.repoName, config))
+	}
+	// Commits referencing a thread say it's been dealt with
+	if client != nil && opts.only != "checks" {
+		markAddressed(client, feedback)
+	}
+	feedback.Score = scoreFeedback(feedback, config.Score)
+
+	// Let analyzer plugins flag comments, e.g. ones mentioning deprecated APIs
+	runAnalyzers(feedback, configuredAnalyzers(config, opts.analyzers))
+	applyRenderers(feedback, config.Renderers)
+	attachDocs(feedback, config.Docs)
+
+	// Test reports and screenshots needed to act on a failure live in artifacts
+	if client != nil && opts.only != "comments" {
+		attachArtifacts(client, feedback)
+		attachCheckAnnotations(client, feedback)
+		filterAnnotationPaths(feedback, opts.paths)
+		filterScope(feedback, opts.scope)
+		attachCheckOwners(client, feedback)
+		if opts.slowChecks {
+			attachSlowChecks(client, feedback)
+		}
+		if opts.routeFailures != "" {
+			routeFailures(client, feedback, opts.routeFailures)
+		}
+	}
+	if client != nil && opts.only != "checks" {
+		// Show who the PR is still waiting on and for how long
+		requests, err := fetchReviewRequests(client, opts.repoName, opts.prNumber)
+		if err != nil && !budgetSkipped(err) {
+			warnf("%v", err)
+		}
+		feedback.ReviewRequests = requests
+	}
+	if opts.artifactsDir != "" {
+		count, err := downloadArtifacts(feedback, opts.artifactsDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error downloading artifacts: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Downloaded artifacts of %d run(s) to %s\n", count, opts.artifactsDir)
+	}
+
+	// Attach local notes; state is optional when outside a git repository
+	var snapshot map[int]commentSnapshot
+	state, stateErr := loadPRState(opts.repoName, opts.prNumber)
+	if stateErr == nil {
+		attachNotes(feedback, state)
+		attachAssignments(feedback, state)
+		markSincePush(feedback, state.Checkpoint)
+
+		// Reviewers often edit comments after posting, so compare against
+		// what was shown last time. The snapshot is taken before retention
+		// and --limit change what's shown, and only saved once the edits
+		// have been shown.
+		if previous, err := loadSnapshot(opts.prNumber); err != nil {
+			warnf("%v", err)
+		} else {
+			detectEdits(feedback, previous)
+			snapshot = previous
+			updateSnapshot(snapshot, feedback)
+		}
+	}
+
+	// Old comment bodies must not leave GitHub under some retention policies
+	if opts.retention > 0 {
+		if redacted := applyRetention(feedback, opts.retention); redacted > 0 {
+			fmt.Fprintf(os.Stderr, "Redacted %d comment(s) older than the retention window\n", redacted)
+		}
+	}
+
+	// Preserve the review record alongside the merge commit
+	if opts.gitNotes {
+		if err := writeGitNotes(feedback); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing git notes: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote review notes for PR #%d to %s on %s\n", feedback.PRNumber, gitNotesRef, feedback.MergeCommitSHA)
+		return
+	}
+
+	// Write reviewer-provided snippets out for scripts to work with
+	if opts.extractDir != "" {
+		count, err := extractCode(feedback, opts.extractDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error extracting code blocks: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Extracted %d code block(s) to %s\n", count, opts.extractDir)
+	}
+
+	// Pick up where the last triage session left off
+	resumeNotice := ""
+	if opts.resume {
+		if stateErr != nil {
+			fmt.Fprintf(os.Stderr, "Error loading state: %v\n", stateErr)
+			os.Exit(1)
+		}
+		hidden := applyResume(feedback, state)
+		if state.LastViewed > 0 {
+			resumeNotice = fmt.Sprintf("Resuming after comment %d", state.LastViewed)
+		} else {
+			resumeNotice = "Resuming"
+		}
+		resumeNotice += fmt.Sprintf(" (%d acknowledged thread(s) hidden)", hidden)
+	}
+
+	// Group related comments so long reviews are easier to navigate
+	if opts.topics {
+		feedback.Topics, err = groupTopics(feedback, opts.topicsCmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error grouping topics: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Put a generated overview of everything above the feedback itself
+	if opts.summarize {
+		command, err := summarizeCommand(opts, config)
+		if err == nil {
+			feedback.Summary, err = summarizeFeedback(feedback, command)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error summarizing feedback: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// The score above covers everything; only the output is capped
+	limitFeedback(feedback, opts.limit)
+
+	// Archive other formats from the same fetch, e.g. for CI artifacts
+	if err := writeOutputFiles(opts, feedback); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Work through the threads one at a time instead of listing them
+	if opts.queue {
+		if client == nil {
+			fmt.Fprintf(os.Stderr, "Error: --queue is only supported for GitHub\n")
+			os.Exit(1)
+		}
+		runQueue(client, opts, feedback)
+		return
+	}
+
+	// Output in requested format
+	if opts.jsonOutput {
+		printJSON(opts, feedback)
+	} else if opts.template != "" {
+		if err := writeTemplate(os.Stdout, opts.template, feedback); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if opts.editPlan {
+		printEditPlan(feedback, opts.editor)
+	} else if opts.format == "prompt" {
+		printPrompt(feedback)
+	} else if opts.format == "markdown" {
+		printMarkdown(feedback)
+	} else if opts.format == "csv" || opts.format == "tsv" {
+		comma := ','
+		if opts.format == "tsv" {
+			comma = '\t'
+		}
+		if err := writeFeedbackTable(os.Stdout, feedback, comma); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if opts.format == "sarif" {
+		if err := writeSARIF(os.Stdout, feedback); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if opts.format == "html" {
+		if err := writeHTML(os.Stdout, feedback); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if opts.format == "junit" {
+		if err := writeJUnit(os.Stdout, feedback); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if opts.format == "quickfix" {
+		writeQuickfix(os.Stdout, feedback)
+	} else if opts.format == "actions" {
+		writeActionsAnnotations(os.Stdout, feedback)
+	} else if opts.format == "rdjson" {
+		if err := writeRDJSON(os.Stdout, feedback); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if opts.minimal {
+		printMinimal(feedback)
+	} else if opts.summary {
+		printSummary(feedback)
+	} else if opts.heatmap {
+		if client == nil {
+			fmt.Fprintf(os.Stderr, "Error: --heatmap is only supported for GitHub\n")
+			os.Exit(1)
+		}
+		files, err := fetchPRFiles(client, opts.repoName, opts.prNumber)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printHeatmap(feedback, scopeFiles(files, opts.scope))
+	} else {
+		if resumeNotice != "" {
+			fmt.Printf("%s%s%s\n\n", colorGray, resumeNotice, colorReset)
+		}
+		if feedback.Counts != nil {
+			counts := feedback.Counts
+			fmt.Printf("%sShallow: %d unresolved thread(s), %d comment(s), %d failing check(s); showing the newest %d of each%s\n\n",
+				colorGray, counts.UnresolvedThreads, counts.GeneralComments, counts.FailingChecks, shallowItems, colorReset)
+		}
+		printHumanReadable(opts, feedback)
+		// Scripts reading the other formats mustn't use up the notice
+		if snapshot != nil {
+			if err := saveSnapshot(opts.prNumber, snapshot); err != nil {
+				warnf("%v", err)
+			}
+		}
+	}
+}
+
+// parseArgs parses the flags and positional arguments common to all commands
+func parseArgs(args []string) *options {
+	opts := &options{}
+
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 		
-		// Handle flags
-		if arg == "--version" || arg == "-v" {
-			fmt.Println("gh-pr-feedback v1.2.0")
-			return
+		// Handle flags
+		if arg == "--version" || arg == "-v" {
+			fmt.Println("gh-pr-feedback v1.2.0")
+			os.Exit(0)
+		}
+		
+		if arg == "--help" || arg == "-h" {
+			printHelp()
+			os.Exit(0)
+		}
+		
+		if arg == "--json" || arg == "-j" {
+			opts.jsonOutput = true
+			continue
+		}
+		
+		if arg == "-q" {
+			opts.format = "quickfix"
+			continue
+		}
+		
+		if arg == "--git-notes" {
+			opts.gitNotes = true
+			continue
+		}
+		
+		if arg == "--analyzer" {
+			if i+1 < len(args) {
+				opts.analyzers = append(opts.analyzers, args[i+1])
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --analyzer requires a command\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--author" || arg == "--exclude-author" {
+			if i+1 < len(args) {
+				login := strings.TrimPrefix(args[i+1], "@")
+				if arg == "--author" {
+					opts.authors = append(opts.authors, login)
+				} else {
+					opts.excludeAuthors = append(opts.excludeAuthors, login)
+				}
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a login\n", arg)
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--association" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --association requires a list, e.g. MEMBER,OWNER\n")
+				os.Exit(1)
+			}
+			for _, association := range strings.Split(args[i+1], ",") {
+				association = strings.ToUpper(strings.TrimSpace(association))
+				if !containsFold(authorAssociations, association) {
+					fmt.Fprintf(os.Stderr, "Error: unknown association %q, expected one of %s\n", association, strings.Join(authorAssociations, ", "))
+					os.Exit(1)
+				}
+				opts.associations = append(opts.associations, association)
+			}
+			i++
+			continue
+		}
+		
+		if arg == "--hide-outdated" || arg == "--only-outdated" {
+			mode := "hide"
+			if arg == "--only-outdated" {
+				mode = "only"
+			}
+			if opts.outdated != "" && opts.outdated != mode {
+				fmt.Fprintf(os.Stderr, "Error: --hide-outdated and --only-outdated can't be used together\n")
+				os.Exit(1)
+			}
+			opts.outdated = mode
+			continue
+		}
+		
+		if arg == "--comments-only" || arg == "--checks-only" {
+			only := "comments"
+			if arg == "--checks-only" {
+				only = "checks"
+			}
+			if opts.only != "" && opts.only != only {
+				fmt.Fprintf(os.Stderr, "Error: --comments-only and --checks-only can't be used together\n")
+				os.Exit(1)
+			}
+			opts.only = only
+			continue
+		}
+		
+		if arg == "--no-bots" || arg == "--bots-only" {
+			bots := "exclude"
+			if arg == "--bots-only" {
+				bots = "only"
+			}
+			if opts.bots != "" && opts.bots != bots {
+				fmt.Fprintf(os.Stderr, "Error: --no-bots and --bots-only can't be used together\n")
+				os.Exit(1)
+			}
+			opts.bots = bots
+			continue
+		}
+		
+		if arg == "--ca-bundle" {
+			if i+1 < len(args) {
+				opts.caBundle = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --ca-bundle requires a PEM file\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--check-conclusions" {
+			if i+1 < len(args) {
+				if err := setFailingConclusions(args[i+1]); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --check-conclusions requires a list such as failure,timed_out\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--commits" {
+			if i+1 < len(args) {
+				opts.commits = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --commits requires a range such as abc123..def456\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--compact" {
+			opts.compact = true
+			continue
+		}
+		
+		if arg == "--indent" {
+			if i+1 < len(args) {
+				opts.indent = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --indent requires a value\n")
+				os.Exit(1)
+			}
+			if n, err := strconv.Atoi(opts.indent); err == nil && n >= 0 && n <= 8 {
+				opts.indent = strings.Repeat(" ", n)
+			} else if opts.indent == "tab" {
+				opts.indent = "\t"
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --indent must be a number of spaces (0-8) or \"tab\"\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--max-requests" {
+			if i+1 < len(args) {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n <= 0 {
+					fmt.Fprintf(os.Stderr, "Error: --max-requests must be a positive number\n")
+					os.Exit(1)
+				}
+				opts.maxRequests = n
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --max-requests requires a number\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--mine" {
+			opts.mine = true
+			continue
+		}
+		
+		if arg == "--stack" {
+			opts.stack = true
+			continue
+		}
+		
+		if arg == "--review-load" {
+			opts.reviewLoad = true
+			continue
+		}
+		
+		if arg == "--watch" {
+			opts.watch = true
+			continue
+		}
+		
+		if arg == "--queue" {
+			opts.queue = true
+			continue
+		}
+		
+		if arg == "--resume" {
+			opts.resume = true
+			continue
+		}
+		
+		if arg == "--download-artifacts" {
+			if i+1 < len(args) {
+				opts.artifactsDir = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --download-artifacts requires a directory\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--estimate" {
+			opts.estimate = true
+			continue
+		}
+		
+		if arg == "--editor" {
+			if i+1 < len(args) && (args[i+1] == "vim" || args[i+1] == "code") {
+				opts.editor = args[i+1]
+				opts.editPlan = true
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --editor must be vim or code\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--extract-code" {
+			if i+1 < len(args) {
+				opts.extractDir = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --extract-code requires a directory\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--base" {
+			if i+1 < len(args) {
+				opts.base = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --base requires a branch or glob, e.g. release/*\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--org" {
+			if i+1 < len(args) {
+				opts.org = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --org requires a value\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--provider" {
+			if i+1 < len(args) {
+				opts.provider = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --provider requires a value\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--print-edit-plan" {
+			opts.editPlan = true
+			continue
+		}
+		
+		if arg == "--retention" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --retention requires a duration\n")
+				os.Exit(1)
+			}
+			retention, err := parseAge(args[i+1])
+			if err != nil || retention == 0 {
+				fmt.Fprintf(os.Stderr, "Error: --retention must be a duration like 90d, 2w or 36h\n")
+				os.Exit(1)
+			}
+			opts.retention = retention
+			i++
+			continue
+		}
+		
+		if arg == "--since" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --since requires an age or date\n")
+				os.Exit(1)
+			}
+			since, err := parseSince(arg, args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			opts.since = since
+			i++
+			continue
+		}
+		
+		if arg == "--grep" {
+			if i+1 < len(args) {
+				opts.grepPattern = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --grep requires a pattern\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--grep-case" {
+			opts.grepCase = true
+			continue
+		}
+		
+		if arg == "--min-reactions" {
+			if i+1 < len(args) {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 1 {
+					fmt.Fprintf(os.Stderr, "Error: --min-reactions requires a positive number\n")
+					os.Exit(1)
+				}
+				opts.minReactions = n
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --min-reactions requires a number\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--reaction" {
+			if i+1 < len(args) && validReaction(normalizeReaction(args[i+1])) {
+				opts.reaction = normalizeReaction(args[i+1])
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --reaction must be one of %s\n", reactionNames)
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--sort" {
+			if i+1 < len(args) && containsFold(sortOrders, args[i+1]) {
+				opts.sort = strings.ToLower(args[i+1])
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --sort must be one of %s\n", strings.Join(sortOrders, ", "))
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--limit" {
+			if i+1 < len(args) {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 1 {
+					fmt.Fprintf(os.Stderr, "Error: --limit requires a positive number\n")
+					os.Exit(1)
+				}
+				opts.limit = n
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --limit requires a number\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--heatmap" {
+			opts.heatmap = true
+			continue
 		}
 		
-		if arg == "--help" || arg == "-h" {
-			printHelp()
-			return
+		if arg == "--include-generated" {
+			opts.includeGenerated = true
+			continue
 		}
 		
-		if arg == "--json" || arg == "-j" {
-			jsonOutput = true
+		if arg == "--path" {
+			if i+1 < len(args) {
+				opts.paths = append(opts.paths, args[i+1])
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --path requires a glob, e.g. 'pkg/server/**'\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--scope" {
+			if i+1 < len(args) {
+				opts.scope = cleanScope(args[i+1])
+				opts.scopeSet = true
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --scope requires a directory, e.g. services/api\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--record" || arg == "--replay" {
+			if i+1 < len(args) {
+				if arg == "--record" {
+					opts.record = args[i+1]
+				} else {
+					opts.replay = args[i+1]
+				}
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a directory\n", arg)
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--route-failures" {
+			if i+1 < len(args) && (args[i+1] == "mention" || args[i+1] == "issue") {
+				opts.routeFailures = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --route-failures requires mention or issue\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--show-minimized" {
+			opts.showMinimized = true
+			continue
+		}
+		
+		if arg == "--include-addressed" {
+			opts.includeAddressed = true
+			continue
+		}
+		
+		if arg == "--include-resolved" {
+			opts.includeResolved = true
+			continue
+		}
+		
+		if arg == "--out" {
+			if i+1 < len(args) {
+				output, err := parseOutputFile(args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				opts.outputs = append(opts.outputs, output)
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --out requires format=file, e.g. json=feedback.json\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--unanswered" {
+			opts.unanswered = true
+			continue
+		}
+		
+		if arg == "--minimal" {
+			opts.minimal = true
+			continue
+		}
+		
+		if arg == "--no-replies" {
+			opts.noReplies = true
+			continue
+		}
+		
+		if arg == "--no-warnings" {
+			opts.noWarnings = true
+			continue
+		}
+		
+		if arg == "--shallow" {
+			opts.shallow = true
+			continue
+		}
+		
+		if arg == "--slow-checks" {
+			opts.slowChecks = true
+			continue
+		}
+		
+		if arg == "--strict" {
+			opts.strict = true
+			continue
+		}
+		
+		if arg == "--summary" {
+			opts.summary = true
+			continue
+		}
+		
+		if arg == "--summarize" {
+			opts.summarize = true
+			continue
+		}
+		
+		if arg == "--summarize-command" {
+			if i+1 < len(args) {
+				opts.summarize = true
+				opts.summarizeCmd = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --summarize-command requires a value\n")
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--format" {
+			if i+1 < len(args) {
+				opts.format = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --format requires a value\n")
+				os.Exit(1)
+			}
+			switch opts.format {
+			case "text":
+			case "json":
+				opts.jsonOutput = true
+			case "prompt", "markdown", "ndjson", "csv", "tsv", "sarif", "rdjson", "actions", "quickfix", "junit", "html":
+			default:
+				fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected text, json, ndjson, markdown, html, csv, tsv, sarif, rdjson, actions, quickfix, junit or prompt)\n", opts.format)
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--template" || arg == "-t" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --template requires a value\n")
+				os.Exit(1)
+			}
+			opts.template = args[i+1]
+			i++
+			if _, err := parseFeedbackTemplate(opts.template); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --template: %v\n", err)
+				os.Exit(1)
+			}
+			continue
+		}
+		
+		if arg == "--topics" {
+			opts.topics = true
+			continue
+		}
+		
+		if arg == "--topics-command" {
+			if i+1 < len(args) {
+				opts.topics = true
+				opts.topicsCmd = args[i+1]
+				i++
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --topics-command requires a value\n")
+				os.Exit(1)
+			}
 			continue
 		}
 		
 		if arg == "--repo" || arg == "-R" {
 			if i+1 < len(args) {
-				repoName = args[i+1]
+				opts.repoName = args[i+1]
 				i++ // Skip next arg
 			} else {
 				fmt.Fprintf(os.Stderr, "Error: --repo requires a value\n")
@@ -103,283 +1233,682 @@ func main() {
 			continue
 		}
 		
-		// Handle positional argument (could be PR number or directory)
 		if !strings.HasPrefix(arg, "-") {
-			// Try to parse as PR number first
-			if num, err := strconv.Atoi(arg); err == nil && num > 0 {
-				prNumber = num
-			} else if targetDir == "" {
-				targetDir = arg
-				// Validate directory exists
-				if _, err := os.Stat(targetDir); os.IsNotExist(err) {
-					fmt.Fprintf(os.Stderr, "Error: Directory '%s' does not exist\n", targetDir)
-					os.Exit(1)
-				}
+			if err := parsePositional(opts, arg); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
 		}
 	}
 	
-	if targetDir == "" {
-		targetDir = "."
+	if opts.targetDir == "" {
+		opts.targetDir = "."
 	}
-
-	// Change to target directory if specified
-	originalDir, err := os.Getwd()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
-		os.Exit(1)
+	// Output read by other programs must be exactly what they expect
+	if opts.jsonOutput || machineFormats[opts.format] || opts.template != "" {
+		disableColors()
 	}
-
-	if targetDir != "." {
-		err = os.Chdir(targetDir)
+	// The budget and CA bundle apply to every client created from here on
+	budget.max = opts.maxRequests
+	quietWarnings(opts.noWarnings)
+	if opts.caBundle != "" {
+		if err := useCABundle(opts.caBundle); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if opts.grepPattern != "" {
+		pattern, err := compileGrep(opts.grepPattern, opts.grepCase)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error changing to directory '%s': %v\n", targetDir, err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		opts.grep = pattern
+	}
+	if opts.record != "" && opts.replay != "" {
+		fmt.Fprintf(os.Stderr, "Error: --record and --replay can't be used together\n")
+		os.Exit(1)
+	}
+	if opts.record != "" || opts.replay != "" {
+		if err := startRecorder(opts.record+opts.replay, opts.replay != ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		// Ensure we change back on exit
-		defer func() {
-			os.Chdir(originalDir)
-		}()
+	}
+	if opts.indent == "" && !opts.compact {
+		opts.indent = "  "
 	}
 
-	client, err := api.DefaultRESTClient()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating GitHub client: %v\n", err)
+	return opts
+}
+
+// printJSON writes v to stdout as JSON, minified with --compact and
+// otherwise indented with --indent (two spaces by default).
+func printJSON(opts *options, v interface{}) {
+	if err := writeJSON(os.Stdout, opts, v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
 		os.Exit(1)
 	}
+}
+
+func writeJSON(w io.Writer, opts *options, v interface{}) error {
+	var output []byte
+	var err error
+	if opts.compact {
+		output, err = json.Marshal(v)
+	} else {
+		output, err = json.MarshalIndent(v, "", opts.indent)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
+
+// parsePositional handles a positional argument, which could be a PR number,
+// a directory, or a branch or commit to look the PR up by
+func parsePositional(opts *options, arg string) error {
+	// Try to parse as PR number first
+	if num, err := strconv.Atoi(arg); err == nil && num > 0 {
+		opts.prNumber = num
+	} else if _, err := os.Stat(filepath.Clean(arg)); os.IsNotExist(err) && opts.ref == "" {
+		opts.ref = arg
+	} else if opts.targetDir == "" {
+		// Clean so Windows paths such as `.\repo\` and `C:\src\repo`
+		// compare and print consistently
+		opts.targetDir = filepath.Clean(arg)
+		// Validate directory exists
+		if info, err := os.Stat(opts.targetDir); err == nil && !info.IsDir() {
+			return fmt.Errorf("'%s' is not a directory", opts.targetDir)
+		}
+	}
+	return nil
+}
+
+// resolvePR changes into the target directory, creates the API client and
+// fills in the repository and PR number from the current branch when they
+// weren't given explicitly.
+func resolvePR(opts *options) *api.RESTClient {
+	provider := &githubProvider{client: newClient(opts), opts: opts}
+	resolveTarget(provider, opts)
+	return provider.client
+}
+
+// resolveTarget fills in the repository and PR number from the current
+// directory when they weren't given explicitly.
+func resolveTarget(provider Provider, opts *options) {
+	// Look the PR up by branch or commit, then carry on as if its number
+	// had been given
+	if opts.prNumber == 0 && opts.ref != "" {
+		github, ok := provider.(*githubProvider)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: looking up a PR by branch or commit is only supported on GitHub\n")
+			os.Exit(1)
+		}
+		repo := opts.repoName
+		var err error
+		if repo == "" {
+			repo, err = provider.CurrentRepo()
+		}
+		if err == nil {
+			opts.prNumber, err = findPRForRef(github.client, repo, opts.ref)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "'%s' isn't a PR number or a directory either\n", opts.ref)
+			os.Exit(1)
+		}
+		opts.repoName = repo
+	}
 
 	// If PR number and repo are provided, use them directly
-	if prNumber > 0 && repoName != "" {
+	if opts.prNumber > 0 && opts.repoName != "" {
 		// Use provided PR number and repo
-	} else if prNumber > 0 {
+	} else if opts.prNumber > 0 {
 		// PR number provided but no repo - try to get repo from current directory
-		_, currentRepo, err := getCurrentPR(client)
+		currentRepo, err := provider.CurrentRepo()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: PR number provided but couldn't determine repository.\n")
 			fmt.Fprintf(os.Stderr, "Use --repo to specify the repository (e.g., --repo owner/name)\n")
 			os.Exit(1)
 		}
-		repoName = currentRepo
+		opts.repoName = currentRepo
 	} else {
 		// No PR number provided - get current PR
-		currentPR, currentRepo, err := getCurrentPR(client)
+		currentRepo := opts.repoName
+		var err error
+		if currentRepo == "" {
+			currentRepo, err = provider.CurrentRepo()
+		}
+		var currentPR int
+		if err == nil {
+			currentPR, err = provider.CurrentPR(currentRepo)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			fmt.Fprintf(os.Stderr, "\nMake sure you're in a git repository with an open PR.\n")
-			fmt.Fprintf(os.Stderr, "You can check PR status with: gh pr status\n")
+			if provider.Name() == "github" {
+				fmt.Fprintf(os.Stderr, "You can check PR status with: gh pr status\n")
+			}
 			fmt.Fprintf(os.Stderr, "Or specify a PR number: gh pr-feedback 123 --repo owner/name\n")
 			os.Exit(1)
 		}
-		prNumber = currentPR
-		repoName = currentRepo
+		opts.prNumber = currentPR
+		opts.repoName = currentRepo
+	}
+}
+
+// newClient changes into the target directory and creates the API client
+func newClient(opts *options) *api.RESTClient {
+	changeDir(opts)
+	return createClient()
+}
+
+// changeDir changes into the target directory if one was specified
+func changeDir(opts *options) {
+	if opts.targetDir != "." {
+		err := os.Chdir(opts.targetDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error changing to directory '%s': %v\n", opts.targetDir, err)
+			os.Exit(1)
+		}
+		opts.targetDir = "."
+	}
+}
+
+// clientOptions configures GitHub API clients, authenticating as the GitHub
+// App when one is set up and charging requests to the budget when capped
+func clientOptions() api.ClientOptions {
+	var opts api.ClientOptions
+	if app := currentAppAuth(); app != nil {
+		opts = app.clientOptions()
+	}
+	if budget.max > 0 || recorder != nil {
+		base := opts.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		opts.Transport = apiTransport(base)
+	}
+	// Replays work offline, without being logged in
+	if recorder != nil && recorder.replay && opts.AuthToken == "" {
+		opts.AuthToken = "replay"
+	}
+	return opts
+}
+
+// apiTransport wraps base with the --max-requests budget and --record or
+// --replay, for every request made to the API, including ones not made
+// through a go-gh client
+func apiTransport(base http.RoundTripper) http.RoundTripper {
+	if budget.max > 0 {
+		base = &budgetTransport{base: base}
+	}
+	if recorder != nil {
+		base = &recordTransport{base: base}
+	}
+	return base
+}
+
+func createClient() *api.RESTClient {
+	client, err := api.NewRESTClient(clientOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating GitHub client: %v\n", err)
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func getCurrentPR(client *api.RESTClient) (int, string, error) {
+	// Get PR for current branch
+	output, err := ghOutput("pr", "view", "--json", "number")
+	if err != nil {
+		return 0, "", fmt.Errorf("no PR found for current branch")
+	}
+
+	var pr struct {
+		Number int `json:"number"`
+	}
+
+	err = json.Unmarshal(output, &pr)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to parse PR data: %w", err)
+	}
+
+	repo, err := getCurrentRepo()
+	if err != nil {
+		return 0, "", err
+	}
+
+	return pr.Number, repo, nil
+}
+
+// getCurrentRepo returns the owner/name of the repository in the current directory
+func getCurrentRepo() (string, error) {
+	output, err := ghOutput("repo", "view", "--json", "nameWithOwner")
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository info: %w", err)
+	}
+
+	var repo struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	}
+
+	err = json.Unmarshal(output, &repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse repository data: %w", err)
+	}
+
+	return repo.NameWithOwner, nil
+}
+
+// fetchPRDetails returns a PRFeedback with only the PR's own details filled in
+func fetchPRDetails(client *api.RESTClient, repo string, prNumber int) (*PRFeedback, error) {
+	var pr struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		State          string `json:"state"`
+		Merged         bool   `json:"merged"`
+		MergeCommitSHA string `json:"merge_commit_sha"`
+		Base           struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	endpoint := fmt.Sprintf("repos/%s/pulls/%d", repo, prNumber)
+	err := client.Get(endpoint, &pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PR details: %w", err)
+	}
+
+	feedback := &PRFeedback{
+		Repo:     repo,
+		PRNumber: pr.Number,
+		Title:    pr.Title,
+		Author:   pr.User.Login,
+		URL:      pr.HTMLURL,
+		State:    pr.State,
+		BaseRef:  pr.Base.Ref,
+	}
+	if pr.Merged {
+		feedback.State = "merged"
+		feedback.MergeCommitSHA = pr.MergeCommitSHA
+	}
+	return feedback, nil
+}
+
+func getPRFeedback(client *api.RESTClient, opts *options, repo string, prNumber int) (*PRFeedback, error) {
+	feedback, err := getPRComments(client, opts, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	attachStatusChecks(client, feedback)
+	return feedback, nil
+}
+
+// getPRComments fetches the PR's details and all of its review feedback,
+// leaving out status checks
+func getPRComments(client *api.RESTClient, opts *options, repo string, prNumber int) (*PRFeedback, error) {
+	// Get PR details
+	feedback, err := fetchPRDetails(client, repo, prNumber)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fetch PR details and review comments
-	feedback, err := getPRFeedback(client, repoName, prNumber)
+	// Get review comments (line-specific comments)
+	reviewComments, err := fetchReviewComments(client, repo, prNumber)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
-		os.Exit(1)
+		return nil, err
 	}
 
-	// Output in requested format
-	if jsonOutput {
-		output, err := json.MarshalIndent(feedback, "", "  ")
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
-			os.Exit(1)
+	// Keep the comments that start threads, with their replies nested under
+	// them. GitHub points every reply at the thread's first comment.
+	replies := make(map[int][]ReviewComment)
+	for _, comment := range reviewComments {
+		if comment.InReplyTo != nil {
+			comment.DiscussionURL = discussionURL(feedback.URL, comment)
+			replies[*comment.InReplyTo] = append(replies[*comment.InReplyTo], comment)
 		}
-		fmt.Println(string(output))
-	} else {
-		printHumanReadable(feedback)
 	}
-}
+	for _, comment := range reviewComments {
+		if comment.InReplyTo == nil { // Top-level comment, not a reply
+			comment.DiscussionURL = discussionURL(feedback.URL, comment)
+			comment.Replies = replies[comment.ID]
+			feedback.Comments = append(feedback.Comments, comment)
+		}
+	}
+	markResolvedThreads(feedback, opts)
 
-func getCurrentPR(client *api.RESTClient) (int, string, error) {
-	// Get PR for current branch
-	cmd := exec.Command("gh", "pr", "view", "--json", "number")
-	output, err := cmd.Output()
+	// Get general PR comments (issue comments)
+	err = eachIssueCommentPage(client, feedback, func(page []ReviewComment) error {
+		feedback.GeneralIssues = append(feedback.GeneralIssues, page...)
+		return nil
+	})
 	if err != nil {
-		return 0, "", fmt.Errorf("no PR found for current branch")
+		return nil, err
 	}
+	markMinimizedComments(feedback, opts.showMinimized)
 
-	var pr struct {
-		Number int `json:"number"`
+	// Get PR reviews
+	if err := attachReviews(client, feedback); err != nil {
+		return nil, err
 	}
+	feedback.ReviewDecision = fetchReviewDecision(repo, prNumber)
+	measureThreads(feedback)
 
-	err = json.Unmarshal(output, &pr)
+	return feedback, nil
+}
+
+// attachReviews records the PR's submitted reviews and adds the ones with a
+// summary as general comments. Comments of the user's own pending review are
+// moved to Pending.
+func attachReviews(client *api.RESTClient, feedback *PRFeedback) error {
+	type review struct {
+		ID         int    `json:"id"`
+		Body       string `json:"body"`
+		State      string `json:"state"`
+		User       struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"user"`
+		AuthorAssoc string `json:"author_association"`
+		SubmittedAt string `json:"submitted_at"`
+		HTMLURL     string `json:"html_url"`
+	}
+	
+	reviewsEndpoint := fmt.Sprintf("repos/%s/pulls/%d/reviews", feedback.Repo, feedback.PRNumber)
+	reviews, err := getAllPages[review](client, reviewsEndpoint)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to parse PR data: %w", err)
+		return fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	// Record every submitted review, and add the ones with a summary as
+	// general comments
+	for _, review := range reviews {
+		if review.State == "PENDING" {
+			attachPendingReview(client, feedback, review.ID, review.Body)
+			continue
+		}
+		reviewURL := fmt.Sprintf("%s#pullrequestreview-%d", feedback.URL, review.ID)
+		feedback.Reviews = append(feedback.Reviews, Review{
+			ID:          review.ID,
+			Author:      review.User.Login,
+			State:       review.State,
+			SubmittedAt: review.SubmittedAt,
+			HTMLURL:     review.HTMLURL,
+		})
+		if review.Body != "" {
+			feedback.GeneralIssues = append(feedback.GeneralIssues, ReviewComment{
+				ID:          review.ID,
+				Body:        review.Body,
+				Author:      review.User.Login,
+				AuthorAssoc: review.AuthorAssoc,
+				AuthorIsBot: review.User.Type == "Bot",
+				State:       "unresolved",
+				ReviewState: review.State,
+				CreatedAt:   review.SubmittedAt,
+				UpdatedAt:   review.SubmittedAt,
+				DiscussionURL: reviewURL,
+				HTMLURL:     review.HTMLURL,
+			})
+		}
 	}
+	return nil
+}
 
-	// Get repository name
-	cmd = exec.Command("gh", "repo", "view", "--json", "nameWithOwner")
-	output, err = cmd.Output()
+// eachIssueCommentPage calls fn with each page of the PR's general comments
+// as soon as it's fetched
+func eachIssueCommentPage(client *api.RESTClient, feedback *PRFeedback, fn func([]ReviewComment) error) error {
+	type issueComment struct {
+		ID         int    `json:"id"`
+		Body       string `json:"body"`
+		AuthorAssoc string `json:"author_association"`
+		User       struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"user"`
+		CreatedAt  string `json:"created_at"`
+		UpdatedAt  string `json:"updated_at"`
+		Reactions  reactions `json:"reactions"`
+		HTMLURL    string `json:"html_url"`
+		NodeID     string `json:"node_id"`
+	}
+	
+	issueEndpoint := fmt.Sprintf("repos/%s/issues/%d/comments", feedback.Repo, feedback.PRNumber)
+	err := eachPage(client, issueEndpoint, func(issueComments []issueComment) error {
+		var comments []ReviewComment
+		for _, comment := range issueComments {
+			comments = append(comments, ReviewComment{
+				ID:          comment.ID,
+				Body:        comment.Body,
+				Author:      comment.User.Login,
+				AuthorAssoc: comment.AuthorAssoc,
+				AuthorIsBot: comment.User.Type == "Bot",
+				State:       "unresolved",
+				CreatedAt:   comment.CreatedAt,
+				UpdatedAt:   comment.UpdatedAt,
+				DiscussionURL: fmt.Sprintf("%s#issuecomment-%d", feedback.URL, comment.ID),
+				Endorsements: comment.Reactions.ThumbsUp,
+				Reactions:    comment.Reactions.counts(),
+				HTMLURL:      comment.HTMLURL,
+				NodeID:       comment.NodeID,
+			})
+		}
+		return fn(comments)
+	})
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to get repository info: %w", err)
+		return fmt.Errorf("failed to fetch issue comments: %w", err)
 	}
+	return nil
+}
 
-	var repo struct {
-		NameWithOwner string `json:"nameWithOwner"`
+// attachStatusChecks adds the PR's failing checks to its feedback, required
+// ones first
+func attachStatusChecks(client *api.RESTClient, feedback *PRFeedback) {
+	statusChecks, jobs, err := getStatusRollup(feedback.Repo, feedback.PRNumber)
+	if err != nil {
+		// Don't fail the whole operation if status checks fail
+		if !budgetSkipped(err) {
+			warnf("failed to fetch status checks: %v", err)
+		}
+		return
 	}
+	feedback.StatusChecks = statusChecks
+	feedback.jobs = jobs
+	markRequiredChecks(client, feedback)
+}
 
-	err = json.Unmarshal(output, &repo)
+// getAllPages fetches every page of a REST list endpoint, 100 items at a
+// time, so large PRs aren't cut off after the first page
+func getAllPages[T any](client *api.RESTClient, endpoint string) ([]T, error) {
+	var items []T
+	err := eachPage(client, endpoint, func(batch []T) error {
+		items = append(items, batch...)
+		return nil
+	})
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to parse repository data: %w", err)
+		return nil, err
 	}
+	return items, nil
+}
 
-	return pr.Number, repo.NameWithOwner, nil
+// eachPage calls fn with each page of a REST list endpoint as soon as it's
+// fetched, 100 items at a time, stopping early if fn fails
+func eachPage[T any](client *api.RESTClient, endpoint string, fn func([]T) error) error {
+	return eachFieldPage(client, endpoint, "", fn)
 }
 
-func getPRFeedback(client *api.RESTClient, repo string, prNumber int) (*PRFeedback, error) {
-	// Get PR details
-	var pr struct {
-		Number int    `json:"number"`
-		Title  string `json:"title"`
-		HTMLURL string `json:"html_url"`
+// eachFieldPage is eachPage for endpoints that return an object with the
+// list under field, such as search results' items
+func eachFieldPage[T any](client *api.RESTClient, endpoint, field string, fn func([]T) error) error {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
 	}
-	endpoint := fmt.Sprintf("repos/%s/pulls/%d", repo, prNumber)
-	err := client.Get(endpoint, &pr)
+	for page := 1; ; page++ {
+		var batch []T
+		path := fmt.Sprintf("%s%sper_page=100&page=%d", endpoint, separator, page)
+		if field == "" {
+			if err := client.Get(path, &batch); err != nil {
+				return err
+			}
+		} else {
+			var response map[string]json.RawMessage
+			if err := client.Get(path, &response); err != nil {
+				return err
+			}
+			if items, ok := response[field]; ok {
+				if err := json.Unmarshal(items, &batch); err != nil {
+					return err
+				}
+			}
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < 100 {
+			return nil
+		}
+	}
+}
+
+// discussionURL links to the conversation a review comment belongs to, which
+// for replies is the thread started by the comment they reply to
+func discussionURL(prURL string, comment ReviewComment) string {
+	id := comment.ID
+	if comment.InReplyTo != nil {
+		id = *comment.InReplyTo
+	}
+	return fmt.Sprintf("%s#discussion_r%d", prURL, id)
+}
+
+// fetchReviewComments returns every line-specific review comment on the PR,
+// including replies.
+func fetchReviewComments(client *api.RESTClient, repo string, prNumber int) ([]ReviewComment, error) {
+	reviewEndpoint := fmt.Sprintf("repos/%s/pulls/%d/comments", repo, prNumber)
+	comments, err := listReviewComments(client, reviewEndpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch PR details: %w", err)
+		return nil, fmt.Errorf("failed to fetch review comments: %w", err)
 	}
+	return comments, nil
+}
 
-	feedback := &PRFeedback{
-		PRNumber: pr.Number,
-		Title:    pr.Title,
-		URL:      pr.HTMLURL,
+// listReviewComments fetches review comments from endpoint, which lists
+// either the whole PR's or a single review's
+func listReviewComments(client *api.RESTClient, endpoint string) ([]ReviewComment, error) {
+	var comments []ReviewComment
+	err := eachReviewCommentPage(client, endpoint, func(page []ReviewComment) error {
+		comments = append(comments, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return comments, nil
+}
 
-	// Get review comments (line-specific comments)
-	var reviewComments []struct {
+// eachReviewCommentPage calls fn with each page of review comments from
+// endpoint as soon as it's fetched
+func eachReviewCommentPage(client *api.RESTClient, endpoint string, fn func([]ReviewComment) error) error {
+	type reviewComment struct {
 		ID              int    `json:"id"`
 		Body            string `json:"body"`
 		Path            string `json:"path"`
 		Line            *int   `json:"line"`
 		StartLine       *int   `json:"start_line"`
 		OriginalLine    *int   `json:"original_line"`
+		Side            string `json:"side"`
 		DiffHunk        string `json:"diff_hunk"`
 		AuthorAssoc     string `json:"author_association"`
 		User            struct {
 			Login string `json:"login"`
+			Type  string `json:"type"`
 		} `json:"user"`
 		InReplyToID     *int   `json:"in_reply_to_id"`
 		CreatedAt       string `json:"created_at"`
 		UpdatedAt       string `json:"updated_at"`
 		Outdated        bool   `json:"outdated"`
 		SubjectType     string `json:"subject_type"`
+		OriginalCommitID string `json:"original_commit_id"`
+		Reactions       reactions `json:"reactions"`
+		HTMLURL         string `json:"html_url"`
+		NodeID          string `json:"node_id"`
 	}
 	
-	reviewEndpoint := fmt.Sprintf("repos/%s/pulls/%d/comments", repo, prNumber)
-	err = client.Get(reviewEndpoint, &reviewComments)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch review comments: %w", err)
-	}
-
-	// Filter unresolved comments (not replies to other comments)
-	for _, comment := range reviewComments {
-		if comment.InReplyToID == nil { // Top-level comment, not a reply
-			feedback.Comments = append(feedback.Comments, ReviewComment{
+	return eachPage(client, endpoint, func(reviewComments []reviewComment) error {
+		var comments []ReviewComment
+		for _, comment := range reviewComments {
+			positionState := commentPositionState(comment.SubjectType, comment.Line, comment.OriginalLine)
+			comments = append(comments, ReviewComment{
 				ID:              comment.ID,
 				Body:            comment.Body,
 				Path:            comment.Path,
 				Line:            comment.Line,
 				StartLine:       comment.StartLine,
 				OriginalLine:    comment.OriginalLine,
+				Side:            comment.Side,
 				DiffHunk:        comment.DiffHunk,
 				Author:          comment.User.Login,
 				AuthorAssoc:     comment.AuthorAssoc,
+				AuthorIsBot:     comment.User.Type == "Bot",
 				State:           "unresolved",
 				InReplyTo:       comment.InReplyToID,
 				CreatedAt:       comment.CreatedAt,
 				UpdatedAt:       comment.UpdatedAt,
-				Outdated:        comment.Outdated,
+				Outdated:        comment.Outdated || positionState == "outdated",
 				SubjectType:     comment.SubjectType,
+				PositionState:   positionState,
+				CommitID:        comment.OriginalCommitID,
+				Endorsements:    comment.Reactions.ThumbsUp,
+				Reactions:       comment.Reactions.counts(),
+				HTMLURL:         comment.HTMLURL,
+				NodeID:          comment.NodeID,
 			})
 		}
-	}
-
-	// Get general PR comments (issue comments)
-	var issueComments []struct {
-		ID         int    `json:"id"`
-		Body       string `json:"body"`
-		AuthorAssoc string `json:"author_association"`
-		User       struct {
-			Login string `json:"login"`
-		} `json:"user"`
-		CreatedAt  string `json:"created_at"`
-		UpdatedAt  string `json:"updated_at"`
-	}
-	
-	issueEndpoint := fmt.Sprintf("repos/%s/issues/%d/comments", repo, prNumber)
-	err = client.Get(issueEndpoint, &issueComments)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch issue comments: %w", err)
-	}
-
-	// Add all general PR comments (not line-specific)
-	for _, comment := range issueComments {
-		feedback.GeneralIssues = append(feedback.GeneralIssues, ReviewComment{
-			ID:          comment.ID,
-			Body:        comment.Body,
-			Author:      comment.User.Login,
-			AuthorAssoc: comment.AuthorAssoc,
-			State:       "unresolved",
-			CreatedAt:   comment.CreatedAt,
-			UpdatedAt:   comment.UpdatedAt,
-		})
-	}
+		return fn(comments)
+	})
+}
 
-	// Get PR reviews
-	var reviews []struct {
-		ID         int    `json:"id"`
-		Body       string `json:"body"`
-		State      string `json:"state"`
-		User       struct {
-			Login string `json:"login"`
-		} `json:"user"`
-		AuthorAssoc string `json:"author_association"`
-		SubmittedAt string `json:"submitted_at"`
-	}
-	
-	reviewsEndpoint := fmt.Sprintf("repos/%s/pulls/%d/reviews", repo, prNumber)
-	err = client.Get(reviewsEndpoint, &reviews)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+// commentPositionState describes where a review comment sits in the current
+// diff, and why it has no live line when it doesn't:
+//   - "line": attached to a line in the current diff
+//   - "file": left on the file as a whole
+//   - "outdated": the line it was left on is no longer part of the diff
+//   - "unknown": GitHub reported no position at all
+func commentPositionState(subjectType string, line, originalLine *int) string {
+	switch {
+	case subjectType == "file":
+		return "file"
+	case line != nil && *line > 0:
+		return "line"
+	case originalLine != nil && *originalLine > 0:
+		return "outdated"
+	default:
+		return "unknown"
 	}
+}
 
-	// Add review summary comments
-	for _, review := range reviews {
-		if review.Body != "" && review.State == "COMMENTED" {
-			feedback.GeneralIssues = append(feedback.GeneralIssues, ReviewComment{
-				ID:          review.ID,
-				Body:        review.Body,
-				Author:      review.User.Login,
-				AuthorAssoc: review.AuthorAssoc,
-				State:       "unresolved",
-				CreatedAt:   review.SubmittedAt,
-				UpdatedAt:   review.SubmittedAt,
-			})
-		}
-	}
+func getStatusChecks(repo string, prNumber int) ([]StatusCheck, error) {
+	statusChecks, _, err := getStatusRollup(repo, prNumber)
+	return statusChecks, err
+}
 
-	// Get status checks
-	statusChecks, err := getStatusChecks(repo, prNumber)
-	if err != nil {
-		// Don't fail the whole operation if status checks fail
-		fmt.Fprintf(os.Stderr, "Warning: failed to fetch status checks: %v\n", err)
-	} else {
-		feedback.StatusChecks = statusChecks
+// getStatusRollup returns the PR's failing checks along with every finished
+// Actions job, which --slow-checks compares against the default branch
+func getStatusRollup(repo string, prNumber int) ([]StatusCheck, []prCheck, error) {
+	if err := budget.take("checks"); err != nil {
+		return nil, nil, err
 	}
 
-	return feedback, nil
-}
-
-func getStatusChecks(repo string, prNumber int) ([]StatusCheck, error) {
 	// Use gh CLI to get status checks
-	cmd := exec.Command("gh", "pr", "view", strconv.Itoa(prNumber), "--repo", repo, "--json", "statusCheckRollup")
-	output, err := cmd.Output()
+	output, err := ghOutput("pr", "view", strconv.Itoa(prNumber), "--repo", repo, "--json", "statusCheckRollup")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get status checks: %w", err)
+		return nil, nil, fmt.Errorf("failed to get status checks: %w", err)
 	}
 
 	var result struct {
@@ -387,6 +1916,9 @@ func getStatusChecks(repo string, prNumber int) ([]StatusCheck, error) {
 			Name         string `json:"name"`
 			Status       string `json:"status"`
 			Conclusion   string `json:"conclusion"`
+			// Commit statuses report a state instead of a conclusion
+			Context      string `json:"context"`
+			State        string `json:"state"`
 			DetailsURL   string `json:"detailsUrl"`
 			WorkflowName string `json:"workflowName"`
 			StartedAt    string `json:"startedAt"`
@@ -396,13 +1928,31 @@ func getStatusChecks(repo string, prNumber int) ([]StatusCheck, error) {
 
 	err = json.Unmarshal(output, &result)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse status checks: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse status checks: %w", err)
 	}
 
+	// Knowing which failures block merging is best-effort
+	required := requiredChecks(repo, prNumber)
+
 	var statusChecks []StatusCheck
+	var jobs []prCheck
 	for _, check := range result.StatusCheckRollup {
-		// Only include failed or errored checks
-		if check.Conclusion == "FAILURE" || check.Conclusion == "ERROR" || check.Conclusion == "CANCELLED" {
+		if check.Conclusion == "" && check.State != "" {
+			check.Name, check.Conclusion = check.Context, check.State
+		}
+		if check.WorkflowName != "" && check.CompletedAt != "" {
+			jobs = append(jobs, prCheck{
+				Name:         check.Name,
+				Status:       check.Status,
+				Conclusion:   check.Conclusion,
+				DetailsURL:   check.DetailsURL,
+				WorkflowName: check.WorkflowName,
+				StartedAt:    check.StartedAt,
+				CompletedAt:  check.CompletedAt,
+			})
+		}
+		// Only include checks whose conclusion counts as failing
+		if isFailingConclusion(check.Conclusion) {
 			statusCheck := StatusCheck{
 				Name:         check.Name,
 				Status:       check.Status,
@@ -411,6 +1961,7 @@ func getStatusChecks(repo string, prNumber int) ([]StatusCheck, error) {
 				WorkflowName: check.WorkflowName,
 				StartedAt:    check.StartedAt,
 				CompletedAt:  check.CompletedAt,
+				Required:     required[check.Name],
 			}
 
 			// Extract run ID if it's a GitHub Actions workflow
@@ -426,7 +1977,33 @@ func getStatusChecks(repo string, prNumber int) ([]StatusCheck, error) {
 		}
 	}
 
-	return statusChecks, nil
+	return statusChecks, jobs, nil
+}
+
+// requiredChecks returns the names of the checks required by the base
+// branch's protection rules. Repositories without required checks make gh
+// exit non-zero, so errors just mean nothing is required.
+func requiredChecks(repo string, prNumber int) map[string]bool {
+	if budget.take("checks") != nil {
+		return nil
+	}
+	output, err := ghOutput("pr", "checks", strconv.Itoa(prNumber), "--repo", repo, "--required", "--json", "name")
+	if err != nil {
+		return nil
+	}
+
+	var checks []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(output, &checks); err != nil {
+		return nil
+	}
+
+	required := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		required[check.Name] = true
+	}
+	return required
 }
 
 func extractRunID(detailsURL string) string {
@@ -442,34 +2019,162 @@ func extractRunID(detailsURL string) string {
 
 
 func printHelp() {
-	fmt.Println("Usage: gh pr-feedback [flags] [pr-number|directory]")
+	fmt.Println("Usage: gh pr-feedback [command] [flags] [pr-number|directory]")
 	fmt.Println("Extracts unresolved review feedback from a PR")
 	fmt.Println("")
+	fmt.Println("Commands:")
+	fmt.Println("  ack <id>         Acknowledge a thread so --resume skips it (--undo to revert)")
+	fmt.Println("  assign <id> <login>  Reply handing a thread to a collaborator and record it locally (--undo to clear)")
+	fmt.Println("  checks           List the PR's checks; --follow <name> shows one's step progress, including reruns, then its log (GitHub only serves logs of finished jobs)")
+	fmt.Println("  context          Show the repo, PR, user, host and API quota that would be used")
+	fmt.Println("  diff-comments    Show the full PR diff with review comments inline")
+	fmt.Println("  doctor           Check gh, auth, token scopes, the repository and config, with fixes")
+	fmt.Println("  export --by-label  Write the PR's deferred feedback as a Markdown doc per follow-up label (-o dir)")
+	fmt.Println("  export --hist <since>  Export every review thread of PRs updated since (e.g. 90d) as CSV or SQL")
+	fmt.Println("  gate             Check the PR against the policy in .github/pr-feedback.yml")
+	fmt.Println("  ids --where k=v  Print the IDs of matching comments, one per line, for xargs (--threads for thread IDs)")
+	fmt.Println("  install-hooks    Install a pre-push hook that marks each push as the start of a review round")
+	fmt.Println("  json-view <file> Render a snapshot saved with --json (\"-\" for stdin)")
+	fmt.Println("  note <id> -m txt Attach a private local note to a thread (--delete to remove)")
+	fmt.Println("  ping <login>     Post a polite nudge to a requested reviewer (--dry-run to preview)")
+	fmt.Println("  reply <id> -m txt  Reply to a thread; --attach-diff <path|range> embeds your local fix as a diff")
+	fmt.Println("  resolve <id>...  Resolve the review threads started by these comments (--undo to reopen, --dry-run to preview)")
+	fmt.Println("  review           Submit your review: --approve, --request-changes or --comment, with -m message")
+	fmt.Println("  revisit          Reopen resolved threads by --author, --path, --match or --resolved-by")
+	fmt.Println("  todo             Write the PR's threads as a Markdown checklist (-o file); --push resolves checked items")
+	fmt.Println("  triage --plan <file>  Apply a YAML plan of replies, resolutions and check reruns (--dry-run to preview, --failed-jobs-only to rerun only a workflow's failed jobs)")
+	fmt.Println("  tui              Browse threads interactively: r reply, R resolve, o open, y copy link")
+	fmt.Println("")
 	fmt.Println("Arguments:")
 	fmt.Println("  pr-number        PR number to view feedback for")
+	fmt.Println("  branch | sha     Branch name or commit SHA to look the PR up by, e.g. feature/login")
 	fmt.Println("  directory        Path to git repository (default: current directory)")
 	fmt.Println("")
 	fmt.Println("Flags:")
+	fmt.Println("      --analyzer <cmd>  Annotate comments with the findings printed by cmd (repeatable)")
+	fmt.Println("      --association <list>  Only show feedback from authors with these associations, e.g. MEMBER,OWNER,COLLABORATOR")
+	fmt.Println("      --author <login>  Only show feedback from this reviewer (repeatable)")
+	fmt.Println("      --base <glob>  Summarize every open PR targeting a matching branch, e.g. release/*")
+	fmt.Println("      --bots-only  Only show comments from bot accounts")
+	fmt.Println("      --ca-bundle <file>  Also trust the PEM certificates in file, e.g. for a TLS-inspecting proxy")
+	fmt.Println("      --check-conclusions <list>  Conclusions that count as failing (default: failure,error,cancelled,timed_out,action_required)")
+	fmt.Println("      --checks-only  Show only failing checks, skipping the comment API calls for a faster fetch")
+	fmt.Println("      --comments-only  Show only review feedback, leaving out checks")
+	fmt.Println("      --commits <a>..<b>  Only show line comments left on commits in the range")
+	fmt.Println("      --compact    Emit minified JSON")
+	fmt.Println("      --download-artifacts <dir>  Download the artifacts of failing Actions runs into dir")
+	fmt.Println("      --editor <vim|code>  With --print-edit-plan, emit a vim quickfix list or a code -g script")
+	fmt.Println("      --estimate           With --mine, --stack, --org or --base, print the GraphQL cost first and abort if it exceeds the quota")
+	fmt.Println("      --exclude-author <login>  Hide feedback from this reviewer, e.g. a noisy bot (repeatable)")
+	fmt.Println("      --extract-code <dir>  Write fenced code blocks from comments to files in dir")
+	fmt.Println("      --format <fmt>  Output format: text, json, ndjson (one object per check or comment, streamed), markdown (for issues and docs), html (a single-file report), csv or tsv (for spreadsheets), sarif (for IDEs and code scanning), rdjson (for reviewdog), actions (workflow annotations), quickfix (for vim -q; -q for short), junit (failing checks as test cases) or prompt (for pasting into an AI assistant)")
+	fmt.Println("      --git-notes  Record the review feedback as a git note on the merge commit")
+	fmt.Println("      --grep <regex>  Only show threads with a comment matching regex, case-insensitively")
+	fmt.Println("      --grep-case  Make --grep case-sensitive")
+	fmt.Println("      --heatmap    Chart unresolved comments per line changed for each file")
 	fmt.Println("  -h, --help       Show help")
+	fmt.Println("      --hide-outdated  Hide threads on code that has since changed")
+	fmt.Println("      --indent <n> Indent JSON with n spaces, or \"tab\" (default: 2)")
+	fmt.Println("      --include-addressed  Show threads referenced by a commit message in full instead of collapsed")
+	fmt.Println("      --include-generated  Show comments on generated and vendored files in full")
+	fmt.Println("      --include-resolved  Also show review threads that have been resolved")
 	fmt.Println("  -j, --json       Output in JSON format")
+	fmt.Println("      --limit <n>  Show at most n comments and n checks")
+	fmt.Println("      --max-requests <n>  Stop after n API requests, fetching comments before checks before extras")
+	fmt.Println("      --min-reactions <n>  Only show threads whose first comment has at least n reactions")
+	fmt.Println("      --minimal    Show each thread in three lines (header, first line, location) for small terminals")
+	fmt.Println("      --mine       Summarize all of your open PRs and find repeated feedback")
+	fmt.Println("      --no-bots    Hide comments from bot accounts, e.g. Dependabot or CodeRabbit")
+	fmt.Println("      --no-replies  Show only the first comment of each thread")
+	fmt.Println("      --no-warnings  Don't print warnings about data that couldn't be fetched (they go to stderr)")
+	fmt.Println("      --only-outdated  Show only threads on code that has since changed, in full")
+	fmt.Println("      --org <org>  Summarize every open PR in an organization")
+	fmt.Println("      --out <fmt=file>  Also write json, markdown, html, csv, tsv, sarif, rdjson, actions, quickfix, junit or prompt output to file (repeatable)")
+	fmt.Println("      --path <glob>  Only show comments and check annotations on matching files (repeatable)")
+	fmt.Println("      --print-edit-plan  List comment locations grouped by file and ordered by line")
+	fmt.Println("      --provider   Code host: github, gitlab, bitbucket or gitea (default: from origin)")
 	fmt.Println("  -R, --repo       Repository name (owner/name)")
+	fmt.Println("      --queue      Work through unresolved threads one at a time: reply, resolve, skip or open in $EDITOR (resumable)")
+	fmt.Println("      --reaction <name>  Only show threads whose first comment has this reaction, e.g. eyes or +1")
+	fmt.Println("      --record <dir>  Save every API response to dir, for --replay")
+	fmt.Println("      --replay <dir>  Serve API responses from a --record directory instead of GitHub (works offline)")
+	fmt.Println("      --retention <age>  Omit bodies of comments older than age (e.g. 90d) from output")
+	fmt.Println("      --review-load  With --org, report open review requests per reviewer")
+	fmt.Println("      --resume     Skip acknowledged threads and continue after the last one viewed")
+	fmt.Println("      --route-failures <mention|issue>  Tell the CODEOWNERS of files a failing check flagged, on the PR or in an issue")
+	fmt.Println("      --scope <dir>  Only show line comments, files and check annotations under dir (default: the current subdirectory; / for all)")
+	fmt.Println("      --shallow    Fetch only totals and the newest items of each section (fast)")
+	fmt.Println("      --show-minimized  Also show comments minimized on GitHub, with the reason")
+	fmt.Println("      --slow-checks  Flag Actions jobs that took much longer than usual on the default branch")
+	fmt.Println("      --since <age|date>  Only show comments and reviews created or updated since, e.g. 2d or 2024-06-01")
+	fmt.Println("      --sort <order>  Order comments and checks by newest, oldest, file or author")
+	fmt.Println("      --stack      Summarize every PR stacked with this one")
+	fmt.Println("      --strict     Fail instead of showing resolved threads when they can't be read over GraphQL")
+	fmt.Println("      --summarize  Prepend a 5-bullet summary generated by a local command, e.g. an LLM")
+	fmt.Println("      --summarize-command <cmd>  Generate the --summarize summary with cmd")
+	fmt.Println("      --summary    Print only the counts and weighted feedback score")
+	fmt.Println("  -t, --template <tmpl>  Format the output with a Go template, e.g. '{{range .Comments}}{{.Path}}:{{.Line}}\\n{{end}}'")
+	fmt.Println("      --topics     Group comments into topics such as error handling or tests")
+	fmt.Println("      --topics-command <cmd>  Cluster topics using embeddings printed by cmd")
+	fmt.Println("      --unanswered  Only show threads still waiting on a reply from the PR's author")
+	fmt.Println("      --watch      Poll the PR and report new, updated and resolved feedback; with --json, as NDJSON events")
 	fmt.Println("  -v, --version    Show version")
 	fmt.Println("")
 	fmt.Println("Examples:")
 	fmt.Println("  gh pr-feedback                      # Current PR in current directory")
 	fmt.Println("  gh pr-feedback 117                  # PR 117 in current repo")
 	fmt.Println("  gh pr-feedback 117 --repo owner/name  # PR 117 in specified repo")
+	fmt.Println("  gh pr-feedback feature/login        # PR for a branch (or a commit SHA)")
 	fmt.Println("  gh pr-feedback /path/to/repo        # Current PR in specified directory")
+	fmt.Println("  gh pr-feedback diff-comments 117    # PR 117's diff annotated with comments")
 }
 
-func printHumanReadable(feedback *PRFeedback) {
+func printHumanReadable(opts *options, feedback *PRFeedback) {
+	comments, generated := splitGenerated(feedback.Comments)
+	comments, addressed := splitAddressed(comments, opts.includeAddressed)
+	comments, outdated := splitOutdated(comments, opts.outdated)
+
 	// Calculate counts
-	commentCount := len(feedback.Comments) + len(feedback.GeneralIssues)
+	commentCount := len(comments) + len(feedback.GeneralIssues)
+	resolvedCount, minimizedCount := 0, 0
+	for _, comment := range comments {
+		if comment.State == "resolved" {
+			resolvedCount++
+		}
+	}
+	for _, comments := range [][]ReviewComment{comments, feedback.GeneralIssues} {
+		for _, comment := range comments {
+			if comment.Minimized && comment.State != "resolved" {
+				minimizedCount++
+			}
+		}
+	}
+	commentCount -= resolvedCount + minimizedCount
 	checkCount := len(feedback.StatusChecks)
+	annotations := annotationsByPath(feedback.StatusChecks)
+	width := terminalWidth()
+	separator := strings.Repeat("─", separatorWidth())
 	
 	// PR Title and metadata
 	fmt.Printf("%s%s #%d%s\n", colorBold, feedback.Title, feedback.PRNumber, colorReset)
-	fmt.Printf("%sOpen%s • %s\n", colorGreen, colorReset, colorGray + feedback.URL + colorReset)
+	fmt.Printf("%s • %s\n", formatPRState(feedback.State), colorGray + feedback.URL + colorReset)
+	if decision, states := formatReviewDecision(feedback.ReviewDecision), formatReviewStates(feedback.Reviews); decision != "" || states != "" {
+		switch {
+		case decision == "":
+			fmt.Println(states)
+		case states == "":
+			fmt.Println(decision)
+		default:
+			fmt.Printf("%s • %s\n", decision, states)
+		}
+	}
+	if len(feedback.ReviewRequests) > 0 {
+		fmt.Printf("%sWaiting on review from %s%s\n", colorGray, formatReviewRequests(feedback.ReviewRequests), colorReset)
+	}
+	if feedback.Summary != "" {
+		fmt.Printf("\n%sSummary%s\n", colorBold, colorReset)
+		printBody(feedback.Summary, width)
+	}
 	
 	// Feedback summary
 	if commentCount > 0 || checkCount > 0 {
@@ -482,63 +2187,118 @@ func printHumanReadable(feedback *PRFeedback) {
 			fmt.Printf("%sX%s Found %d failing check(s)\n", colorRed, colorReset, checkCount)
 		}
 	}
+	if resolvedCount > 0 {
+		fmt.Printf("%s✓%s Showing %d resolved thread(s)\n", colorGreen, colorReset, resolvedCount)
+	}
+	if minimizedCount > 0 {
+		fmt.Printf("%s✓%s Showing %d minimized comment(s)\n", colorGreen, colorReset, minimizedCount)
+	}
+	if len(feedback.Pending) > 0 {
+		fmt.Printf("%s✎%s You have %d pending comment(s) in an unsubmitted review\n", colorCyan, colorReset, len(feedback.Pending))
+	}
+	printCheckpointSummary(feedback)
 	fmt.Println()
 
+	if len(feedback.Topics) > 0 {
+		printTopics(feedback)
+	}
+
 	// Review Comments Section
-	if len(feedback.Comments) > 0 || len(feedback.GeneralIssues) > 0 {
+	if len(comments) > 0 || len(feedback.GeneralIssues) > 0 || len(annotations) > 0 {
 		// First show general review comments
 		if len(feedback.GeneralIssues) > 0 {
 			for _, review := range feedback.GeneralIssues {
 				// Review header like GitHub
-				fmt.Printf("%s%s%s commented %s(%s)%s • %s", 
-					colorBold, review.Author, colorReset,
+				fmt.Printf("%s%s%s %s %s(%s)%s • %s", 
+					colorBold+authorColor(review.Author), hyperlink(review.DiscussionURL, review.Author), colorReset,
+					reviewVerb(review.ReviewState),
 					colorGray, strings.Title(strings.ToLower(review.AuthorAssoc)), colorReset,
 					colorGray)
 				
 				if review.CreatedAt != "" {
 					if t, err := parseTime(review.CreatedAt); err == nil {
-						ago := formatTimeAgo(time.Since(t))
+						ago := formatTimeAgo(now().Sub(t))
 						fmt.Printf("%s", ago)
 					}
 				}
-				fmt.Printf("%s\n\n", colorReset)
+				fmt.Print(colorReset)
+				fmt.Print(formatMinimized(review))
+				if review.PreviousBody != "" {
+					fmt.Printf(" %s• Edited%s", colorCyan, colorReset)
+				}
+				fmt.Print(formatSincePush(review))
+				fmt.Print(formatAssignee(review))
+				fmt.Print(formatEndorsements(review.Endorsements))
+				fmt.Printf("%s\n\n", formatBadges(review.Annotations))
 				
 				// Review body
-				lines := strings.Split(review.Body, "\n")
-				for _, line := range lines {
-					fmt.Printf("%s\n", line)
+				if review.PreviousBody != "" {
+					printEdit(review)
+				} else if review.Summary != "" {
+					printBody(review.Summary, width)
+				} else {
+					printBody(review.Body, width)
 				}
+				printAnnotations(review.Annotations, width)
+				printNote(review.Note, width)
+				printPermalink(review.HTMLURL)
 				fmt.Println()
 			}
 		}
 		
-		// Then show file-specific comments
-		if len(feedback.Comments) > 0 {
-			fmt.Println(strings.Repeat("─", 100))
+		// Then show file-specific comments, with the check annotations on
+		// each file after its last comment
+		if len(comments) > 0 || len(annotations) > 0 {
+			fmt.Println(separator)
 			fmt.Println()
 			
-			for i, comment := range feedback.Comments {
+			last := make(map[string]int)
+			for i, comment := range comments {
+				last[comment.Path] = i
+			}
+			for i, comment := range comments {
 				// Author and metadata on one line
-				fmt.Printf("%s%s%s", colorBold, comment.Author, colorReset)
+				fmt.Printf("%s%s%s", colorBold+authorColor(comment.Author), hyperlink(comment.DiscussionURL, comment.Author), colorReset)
 				if comment.AuthorAssoc != "" && comment.AuthorAssoc != "NONE" {
 					fmt.Printf(" • %s", colorGray + strings.ToLower(comment.AuthorAssoc) + colorReset)
 				}
 				if comment.CreatedAt != "" {
 					if t, err := parseTime(comment.CreatedAt); err == nil {
-						ago := formatTimeAgo(time.Since(t))
+						ago := formatTimeAgo(now().Sub(t))
 						fmt.Printf(" • %s%s%s", colorGray, ago, colorReset)
 					}
 				}
 				if comment.Outdated {
 					fmt.Printf(" %s• Outdated%s", colorYellow, colorReset)
 				}
-				fmt.Println("\n")
+				if comment.State == "resolved" {
+					fmt.Printf(" %s• Resolved%s", colorGreen, colorReset)
+				}
+				if comment.AddressedIn != "" {
+					fmt.Printf(" %s• Addressed in %s%s", colorYellow, shortSHA(comment.AddressedIn), colorReset)
+				}
+				fmt.Print(formatMinimized(comment))
+				if comment.PreviousBody != "" {
+					fmt.Printf(" %s• Edited%s", colorCyan, colorReset)
+				}
+				fmt.Print(formatSincePush(comment))
+				fmt.Print(formatAssignee(comment))
+				fmt.Print(formatEndorsements(comment.Endorsements))
+				fmt.Print(formatThreadSize(comment))
+				fmt.Print(formatBadges(comment.Annotations))
+				fmt.Print("\n\n")
 				
 				// Comment body
-				lines := strings.Split(comment.Body, "\n")
-				for _, line := range lines {
-					fmt.Printf("%s\n", line)
+				if comment.PreviousBody != "" {
+					printEdit(comment)
+				} else if comment.Summary != "" {
+					printBody(comment.Summary, width)
+				} else {
+					printBody(comment.Body, width)
 				}
+				printAnnotations(comment.Annotations, width)
+				printNote(comment.Note, width)
+				printPermalink(comment.HTMLURL)
 				fmt.Println()
 				
 				// File location in a box
@@ -546,39 +2306,72 @@ func printHumanReadable(feedback *PRFeedback) {
 					fmt.Printf("%s%s", colorBlue, comment.Path)
 					if comment.Line != nil && *comment.Line > 0 {
 						fmt.Printf(" on line %d", *comment.Line)
+					} else if comment.PositionState == "outdated" {
+						fmt.Printf(" on original line %d %s(no longer in the diff)", *comment.OriginalLine, colorGray)
 					}
 					fmt.Printf("%s\n", colorReset)
+					printDocs(comment.Docs)
 					
-					// Show diff context
-					if comment.DiffHunk != "" && !comment.Outdated {
+					// Show diff context, falling back to the hunk the comment
+					// was originally left on when its line has moved away
+					if comment.DiffHunk != "" {
 						fmt.Println()
+						if comment.PositionState == "outdated" {
+							fmt.Printf("    %sOriginal context:%s\n", colorGray, colorReset)
+						}
 						printDiffHunk(comment.DiffHunk)
 					}
 				}
 				
+				printReplies(comment.Replies, width)
+				if last[comment.Path] == i && comment.Path != "" {
+					printFileAnnotations(annotations[comment.Path], width)
+					delete(annotations, comment.Path)
+				}
+				
 				// Separator between comments
-				if i < len(feedback.Comments)-1 {
-					fmt.Println("\n" + strings.Repeat("─", 100) + "\n")
+				if i < len(comments)-1 {
+					fmt.Println("\n" + separator + "\n")
 				}
 			}
+			printRemainingAnnotations(annotations, width, separator, len(comments) > 0)
 		}
 	}
 
+	printPendingReview(feedback.Pending, width, separator)
+
+	if len(generated) > 0 {
+		printGeneratedSummary(generated, separator)
+	}
+	if len(addressed) > 0 {
+		printAddressedSummary(addressed, separator)
+	}
+	if len(outdated) > 0 {
+		printOutdatedSummary(outdated, separator)
+	}
+
 
 	// Status Checks Section
 	if len(feedback.StatusChecks) > 0 {
-		fmt.Println("\n" + strings.Repeat("─", 100) + "\n")
-		fmt.Printf("%sFailed Checks%s\n\n", colorBold, colorReset)
+		fmt.Println("\n" + separator + "\n")
+		fmt.Printf("%sFailed Checks%s%s\n\n", colorBold, colorReset, formatRequiredCount(feedback.StatusChecks))
 		
 		for _, check := range feedback.StatusChecks {
 			symbol := "✗"
 			symbolColor := colorRed
-			if check.Conclusion == "CANCELLED" {
+			switch check.Conclusion {
+			case "CANCELLED":
 				symbol = "⊘"
 				symbolColor = colorYellow
+			case "TIMED_OUT":
+				symbol = "⏱"
+				symbolColor = colorYellow
+			case "ACTION_REQUIRED":
+				symbol = "!"
+				symbolColor = colorYellow
 			}
 			
-			fmt.Printf("%s%s%s %s", symbolColor, symbol, colorReset, check.Name)
+			fmt.Printf("%s%s%s %s%s%s", symbolColor, symbol, colorReset, check.Name, formatCheckProvider(check), formatCheckRequirement(check))
 			
 			// Duration
 			if check.StartedAt != "" && check.CompletedAt != "" {
@@ -593,10 +2386,32 @@ func printHumanReadable(feedback *PRFeedback) {
 			if check.CheckCommand != "" {
 				fmt.Printf(" → %s%s%s", colorCyan, check.CheckCommand, colorReset)
 			}
+			if len(check.Owners) > 0 {
+				fmt.Printf(" %sowned by %s%s", colorPurple, strings.Join(check.Owners, ", "), colorReset)
+			}
 			fmt.Println()
+			for _, artifact := range check.Artifacts {
+				fmt.Printf("    %s↳ %s (%s)", colorGray, artifact.Name, formatBytes(artifact.Size))
+				if artifact.Expired {
+					fmt.Printf(", expired")
+				}
+				fmt.Printf("%s\n", colorReset)
+			}
 		}
 	}
+	printSlowChecks(feedback.SlowChecks, separator)
+}
 
+// formatPRState returns the PR's state as a colored label
+func formatPRState(state string) string {
+	switch state {
+	case "merged":
+		return colorPurple + "Merged" + colorReset
+	case "closed":
+		return colorRed + "Closed" + colorReset
+	default:
+		return colorGreen + "Open" + colorReset
+	}
 }
 
 func parseTime(timeStr string) (time.Time, error) {
@@ -613,6 +2428,133 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
 }
 
+func printBody(body string, width int) {
+	for _, line := range wrapBody(body, width) {
+		fmt.Printf("%s\n", line)
+	}
+}
+
+// printReplies prints the rest of a thread indented under its first comment
+func printReplies(replies []ReviewComment, width int) {
+	if width > 0 {
+		width -= 6
+	}
+	for _, reply := range replies {
+		fmt.Printf("\n    %s↳%s %s%s%s", colorGray, colorReset, colorBold+authorColor(reply.Author), hyperlink(reply.DiscussionURL, reply.Author), colorReset)
+		if t, err := parseTime(reply.CreatedAt); err == nil {
+			fmt.Printf(" • %s%s%s", colorGray, formatTimeAgo(now().Sub(t)), colorReset)
+		}
+		fmt.Print(formatEndorsements(reply.Endorsements))
+		fmt.Println()
+		for _, line := range wrapBody(reply.Body, width) {
+			fmt.Printf("      %s\n", line)
+		}
+		if reply.HTMLURL != "" {
+			fmt.Printf("      %s%s%s\n", colorGray, reply.HTMLURL, colorReset)
+		}
+	}
+}
+
+// printPermalink prints a comment's link to GitHub, dimmed so it doesn't
+// compete with the comment
+func printPermalink(url string) {
+	if url != "" {
+		fmt.Printf("%s%s%s\n", colorGray, url, colorReset)
+	}
+}
+
+// fetchFeedback fetches the PR's feedback from its provider, leaving out
+// what --comments-only or --checks-only don't need. The REST client is
+// returned for GitHub, for the extras fetched afterwards.
+func fetchFeedback(provider Provider, opts *options) (*PRFeedback, *api.RESTClient, error) {
+	if opts.shallow {
+		feedback, err := fetchShallowFeedback(createGraphQLClient(), opts, opts.repoName, opts.prNumber)
+		return feedback, nil, err
+	}
+	github, ok := provider.(*githubProvider)
+	if !ok {
+		feedback, err := provider.FetchFeedback(opts.repoName, opts.prNumber)
+		return feedback, nil, err
+	}
+
+	client := github.client
+	switch opts.only {
+	case "checks":
+		// The comment endpoints are most of the requests, and aren't
+		// needed when iterating on CI
+		feedback, err := fetchPRDetails(client, opts.repoName, opts.prNumber)
+		if err == nil {
+			attachStatusChecks(client, feedback)
+		}
+		return feedback, client, err
+	case "comments":
+		feedback, err := getPRComments(client, opts, opts.repoName, opts.prNumber)
+		return feedback, client, err
+	}
+	feedback, err := provider.FetchFeedback(opts.repoName, opts.prNumber)
+	return feedback, client, err
+}
+
+// filterFeedback narrows fetched feedback down to what the flags ask for
+func filterFeedback(feedback *PRFeedback, opts *options) {
+	restrictFeedback(feedback, opts.only)
+	classifyChecks(feedback.StatusChecks)
+	if opts.unanswered {
+		filterUnanswered(feedback)
+	}
+	if opts.noReplies {
+		dropReplies(feedback)
+	}
+	filterAuthors(feedback, opts.authors, opts.excludeAuthors)
+	filterBots(feedback, opts.bots)
+	filterAssociations(feedback, opts.associations)
+	filterPaths(feedback, opts.paths)
+	filterScope(feedback, opts.scope)
+	filterSince(feedback, opts.since)
+	filterGrep(feedback, opts.grep)
+	filterReactions(feedback, opts.minReactions, opts.reaction)
+	filterOutdated(feedback, opts.outdated)
+}
+
+// restrictFeedback leaves only the review feedback for --comments-only, or
+// only the checks for --checks-only
+func restrictFeedback(feedback *PRFeedback, only string) {
+	switch only {
+	case "comments":
+		feedback.StatusChecks = nil
+	case "checks":
+		feedback.Comments = nil
+		feedback.GeneralIssues = nil
+		feedback.Reviews = nil
+		feedback.ReviewDecision = ""
+		feedback.Pending = nil
+	}
+}
+
+// dropReplies leaves only the first comment of each thread, for --no-replies
+func dropReplies(feedback *PRFeedback) {
+	for i := range feedback.Comments {
+		feedback.Comments[i].Replies = nil
+	}
+}
+
+func printNote(note string, width int) {
+	if note == "" {
+		return
+	}
+	if width > 0 {
+		width -= 2
+	}
+	fmt.Println()
+	for i, line := range wrapBody(note, width) {
+		if i == 0 {
+			fmt.Printf("%s✎ %s%s\n", colorPurple, line, colorReset)
+		} else {
+			fmt.Printf("%s  %s%s\n", colorPurple, line, colorReset)
+		}
+	}
+}
+
 func printDiffHunk(diffHunk string) {
 	lines := strings.Split(diffHunk, "\n")
 	for _, line := range lines {
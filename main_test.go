@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePositional(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "repo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	tests := []struct {
+		name      string
+		arg       string
+		targetDir string
+		ref       string
+		prNumber  int
+		wantErr   bool
+	}{
+		{name: "pr number", arg: "42", prNumber: 42},
+		{name: "relative directory", arg: "repo", targetDir: "repo"},
+		{name: "dot relative directory", arg: "./repo", targetDir: "repo"},
+		{name: "trailing slash", arg: "repo/", targetDir: "repo"},
+		{name: "trailing separator", arg: "repo" + string(filepath.Separator), targetDir: "repo"},
+		{name: "absolute directory", arg: filepath.Join(root, "repo") + string(filepath.Separator), targetDir: filepath.Join(root, "repo")},
+		{name: "current directory", arg: ".", targetDir: "."},
+		{name: "missing path is a ref", arg: "feature/login", ref: "feature/login"},
+		{name: "file", arg: "notes.txt", targetDir: "notes.txt", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &options{}
+			err := parsePositional(opts, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePositional(%q) error = %v, want error %v", tt.arg, err, tt.wantErr)
+			}
+			if opts.targetDir != tt.targetDir {
+				t.Errorf("targetDir = %q, want %q", opts.targetDir, tt.targetDir)
+			}
+			if opts.ref != tt.ref {
+				t.Errorf("ref = %q, want %q", opts.ref, tt.ref)
+			}
+			if opts.prNumber != tt.prNumber {
+				t.Errorf("prNumber = %d, want %d", opts.prNumber, tt.prNumber)
+			}
+		})
+	}
+}
+
+func TestSpreadsheetCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"=HYPERLINK(\"https://example.com\")", "'=HYPERLINK(\"https://example.com\")"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"", ""},
+		{"Looks good", "Looks good"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := spreadsheetCell(tt.value); got != tt.want {
+			t.Errorf("spreadsheetCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestApplyRetentionRedactsDerivedText(t *testing.T) {
+	old := now().Add(-100 * 24 * time.Hour).Format(time.RFC3339)
+	recent := now().Add(-time.Hour).Format(time.RFC3339)
+	secrets := []string{"secret body", "secret edit", "secret summary", "secret analysis", "secret reply", "secret reply edit"}
+	feedback := &PRFeedback{
+		Comments: []ReviewComment{{
+			Body:         "secret body",
+			PreviousBody: "secret edit",
+			Summary:      "secret summary",
+			Annotations:  []Annotation{{Analyzer: "deprecated", Badge: "deprecated", Message: "secret analysis"}},
+			CreatedAt:    old,
+			Replies: []ReviewComment{
+				{Body: "secret reply", PreviousBody: "secret reply edit", CreatedAt: old},
+				{Body: "recent reply", CreatedAt: recent},
+			},
+		}},
+		GeneralIssues: []ReviewComment{{Body: "recent comment", CreatedAt: recent}},
+	}
+
+	if redacted := applyRetention(feedback, 90*24*time.Hour); redacted != 2 {
+		t.Errorf("applyRetention redacted %d comment(s), want 2", redacted)
+	}
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range secrets {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("redacted feedback still contains %q: %s", secret, data)
+		}
+	}
+	for _, kept := range []string{"recent reply", "recent comment", "deprecated"} {
+		if !strings.Contains(string(data), kept) {
+			t.Errorf("feedback lost %q: %s", kept, data)
+		}
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"cmd/*.go", "cmd/main.go", true},
+		{"cmd/*.go", "cmd/sub/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/sub/main.go", true},
+		{"docs/**", "docs/guide/intro.md", true},
+		{"docs/**", "docsite/index.md", false},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file/.txt", false},
+		{"a+b.md", "a+b.md", true},
+		{"a+b.md", "aab.md", false},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFilterPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		globs []string
+		want  []string
+	}{
+		{name: "no globs keeps everything", globs: nil, want: []string{"api/server.go", "web/app.ts", "README.md"}},
+		{name: "directory glob", globs: []string{"api/**"}, want: []string{"api/server.go"}},
+		{name: "any of several", globs: []string{"**/*.ts", "*.md"}, want: []string{"web/app.ts", "README.md"}},
+		{name: "no match", globs: []string{"docs/**"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedback := &PRFeedback{
+				Comments: []ReviewComment{
+					{ID: 1, Path: "api/server.go"},
+					{ID: 2, Path: "web/app.ts"},
+					{ID: 3, Path: "README.md"},
+				},
+				GeneralIssues: []ReviewComment{{ID: 4, Body: "Looks good"}},
+			}
+			filterPaths(feedback, tt.globs)
+			var got []string
+			for _, comment := range feedback.Comments {
+				got = append(got, comment.Path)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filterPaths(%q) kept %q, want %q", tt.globs, got, tt.want)
+			}
+			if wantGeneral := len(tt.globs) == 0; (len(feedback.GeneralIssues) > 0) != wantGeneral {
+				t.Errorf("filterPaths(%q) kept %d general comment(s)", tt.globs, len(feedback.GeneralIssues))
+			}
+		})
+	}
+}
+
+func TestCodeownersMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "any/file.go", true},
+		{"*.js", "app.js", true},
+		{"*.js", "web/src/app.js", true},
+		{"*.js", "app.jsx", false},
+		{"docs/", "docs/intro.md", true},
+		{"docs/", "web/docs/intro.md", true},
+		{"/docs/", "docs/intro.md", true},
+		{"/docs/", "web/docs/intro.md", false},
+		{"apps/web", "apps/web/src/main.ts", true},
+		{"apps/web", "other/apps/web/main.ts", false},
+		{"apps/web", "apps/website/main.ts", false},
+		{"/build/*.log", "build/out.log", true},
+		{"/build/*.log", "build/nested/out.log", false},
+		{"**/migrations", "db/migrations/001.sql", true},
+	}
+	for _, tt := range tests {
+		if got := codeownersMatch(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("codeownersMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCommentSeverity(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"This is a blocker: the migration drops data", severityBlocking},
+		{"Blockers: tests are failing", severityBlocking},
+		{"[blocking] needs a lock", severityBlocking},
+		{"You must fix the error handling", severityBlocking},
+		{"issue (blocking): races on close", severityBlocking},
+		{"**blocker:** leaks the token", severityBlocking},
+		{"Not a blocker, but consider renaming", severityNormal},
+		{"Non-blocker: maybe cache this", severityNormal},
+		{"No blocker here, just curious", severityNormal},
+		{"This isn't a blocker", severityNormal},
+		{"Not a blocker here, but the other one is a blocker", severityBlocking},
+		{"Uses the adblocker package", severityNormal},
+		{"roadblockers ahead", severityNormal},
+		{"nit: trailing whitespace", severityNit},
+		{"suggestion (non-blocking): extract a helper", severityNit},
+		{"Could this use a map?", severityNormal},
+	}
+	for _, tt := range tests {
+		if got := commentSeverity(tt.body); got != tt.want {
+			t.Errorf("commentSeverity(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "90d", want: 90 * 24 * time.Hour},
+		{value: "2w", want: 14 * 24 * time.Hour},
+		{value: "36h", want: 36 * time.Hour},
+		{value: "90m", want: 90 * time.Minute},
+		{value: "0d", want: 0},
+		{value: "-1d", wantErr: true},
+		{value: "-2h", wantErr: true},
+		{value: "d", wantErr: true},
+		{value: "1.5d", wantErr: true},
+		{value: "soon", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAge(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAge(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseHunkHeader(t *testing.T) {
+	tests := []struct {
+		header  string
+		wantOld int
+		wantNew int
+	}{
+		{"@@ -12,7 +12,9 @@ func main() {", 12, 12},
+		{"@@ -1 +1 @@", 1, 1},
+		{"@@ -0,0 +1,25 @@", 0, 1},
+		{"@@ -40,6 +38,8 @@", 40, 38},
+		{"@@", 0, 0},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		gotOld, gotNew := parseHunkHeader(tt.header)
+		if gotOld != tt.wantOld || gotNew != tt.wantNew {
+			t.Errorf("parseHunkHeader(%q) = %d, %d, want %d, %d", tt.header, gotOld, gotNew, tt.wantOld, tt.wantNew)
+		}
+	}
+}
+
+func TestParseOutputFile(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    outputFile
+		wantErr bool
+	}{
+		{value: "json=feedback.json", want: outputFile{Format: "json", Path: "feedback.json"}},
+		{value: "csv=out/feedback.csv", want: outputFile{Format: "csv", Path: "out/feedback.csv"}},
+		{value: "json=a=b.json", want: outputFile{Format: "json", Path: "a=b.json"}},
+		{value: "json=", wantErr: true},
+		{value: "feedback.json", wantErr: true},
+		{value: "yaml=feedback.yaml", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseOutputFile(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseOutputFile(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseOutputFile(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// printMinimal prints each thread as exactly three lines, its header, the
+// first line of its body and its location, cut to the terminal's width, for
+// 80x24 sessions over SSH and tmux popups. Failing checks take a line each.
+func printMinimal(feedback *PRFeedback) {
+	width := terminalWidth()
+	fit := func(s string) string {
+		if width <= 0 {
+			return s
+		}
+		return runewidth.Truncate(s, width, "…")
+	}
+
+	for _, comments := range [][]ReviewComment{feedback.GeneralIssues, feedback.Comments} {
+		for _, comment := range comments {
+			header := []string{"@" + comment.Author}
+			if t, err := parseTime(comment.CreatedAt); err == nil {
+				header = append(header, formatTimeAgo(now().Sub(t)))
+			}
+			if comment.Outdated {
+				header = append(header, "outdated")
+			}
+			if comment.State == "resolved" {
+				header = append(header, "resolved")
+			}
+			switch comment.ReplyCount {
+			case 0:
+			case 1:
+				header = append(header, "1 reply")
+			default:
+				header = append(header, fmt.Sprintf("%d replies", comment.ReplyCount))
+			}
+
+			location := "general comment"
+			if comment.Path != "" {
+				location = comment.Path
+				if line := commentLine(comment); line > 0 {
+					location = fmt.Sprintf("%s:%d", comment.Path, line)
+				}
+			}
+			fmt.Printf("%s%s%s\n", colorBold, fit(strings.Join(header, " • ")), colorReset)
+			fmt.Println(fit(firstLine(comment.Body)))
+			fmt.Printf("%s%s%s\n", colorBlue, fit(location), colorReset)
+		}
+	}
+	for _, check := range feedback.StatusChecks {
+		fmt.Printf("%s✗%s %s\n", colorRed, colorReset, fit(fmt.Sprintf("%s (%s)", check.Name, strings.ToLower(check.Conclusion))))
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+)
+
+// minimizedBatch is how many comments one GraphQL lookup covers
+const minimizedBatch = 100
+
+const minimizedQuery = `query($ids: [ID!]!) {
+  nodes(ids: $ids) {
+    ... on Minimizable { isMinimized minimizedReason }
+  }
+}`
+
+// markMinimizedComments looks up which comments have been minimized, which
+// REST doesn't report, and drops them unless --show-minimized is set. Without
+// the lookup every comment is kept.
+func markMinimizedComments(feedback *PRFeedback, show bool) {
+	var ids []string
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for _, comment := range comments {
+			if comment.NodeID != "" {
+				ids = append(ids, comment.NodeID)
+			}
+			for _, reply := range comment.Replies {
+				if reply.NodeID != "" {
+					ids = append(ids, reply.NodeID)
+				}
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	reasons, err := fetchMinimized(ids)
+	if err != nil {
+		// Tokens that can't use GraphQL were already reported by the thread lookup
+		if !budgetSkipped(err) && !isScopeError(err) {
+			warnf("couldn't tell minimized comments apart, showing all of them: %v", err)
+		}
+		return
+	}
+	feedback.Comments = filterMinimized(feedback.Comments, reasons, show)
+	feedback.GeneralIssues = filterMinimized(feedback.GeneralIssues, reasons, show)
+}
+
+// filterMinimized drops the minimized comments and replies, or with show
+// annotates them with the reason
+func filterMinimized(comments []ReviewComment, reasons map[string]string, show bool) []ReviewComment {
+	kept := comments[:0]
+	for _, comment := range comments {
+		reason, ok := reasons[comment.NodeID]
+		if ok {
+			if !show {
+				continue
+			}
+			comment.Minimized = true
+			comment.MinimizedReason = reason
+		}
+		if len(comment.Replies) > 0 {
+			comment.Replies = filterMinimized(comment.Replies, reasons, show)
+		}
+		kept = append(kept, comment)
+	}
+	return kept
+}
+
+// fetchMinimized returns the minimized reason of each minimized comment, by
+// node ID
+func fetchMinimized(ids []string) (map[string]string, error) {
+	client := createGraphQLClient()
+	reasons := make(map[string]string)
+	for start := 0; start < len(ids); start += minimizedBatch {
+		batch := ids[start:min(start+minimizedBatch, len(ids))]
+		var response struct {
+			Nodes []*struct {
+				IsMinimized     bool   `json:"isMinimized"`
+				MinimizedReason string `json:"minimizedReason"`
+			} `json:"nodes"`
+		}
+		if err := client.Do(minimizedQuery, map[string]interface{}{"ids": batch}, &response); err != nil {
+			return nil, fmt.Errorf("failed to fetch minimized comments: %w", err)
+		}
+		for i, node := range response.Nodes {
+			if node != nil && node.IsMinimized && i < len(batch) {
+				reasons[batch[i]] = node.MinimizedReason
+			}
+		}
+	}
+	return reasons, nil
+}
+
+// formatMinimized returns the header badge of a minimized comment
+func formatMinimized(comment ReviewComment) string {
+	if !comment.Minimized {
+		return ""
+	}
+	if comment.MinimizedReason == "" {
+		return fmt.Sprintf(" %s• Minimized%s", colorGray, colorReset)
+	}
+	return fmt.Sprintf(" %s• Minimized (%s)%s", colorGray, comment.MinimizedReason, colorReset)
+}
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// prRef identifies a pull request in a repository
+type prRef struct {
+	Repo   string
+	Number int
+}
+
+// MultiFeedback is the result of a multi-PR mode such as --mine or --stack
+type MultiFeedback struct {
+	PullRequests  []*PRFeedback    `json:"pull_requests"`
+	Duplicates    []DuplicateGroup `json:"duplicates,omitempty"`
+	CheckClusters []CheckCluster   `json:"check_clusters,omitempty"`
+}
+
+// CheckCluster is a check failing on several PRs at once, which usually
+// points at broken shared CI rather than the PRs themselves.
+type CheckCluster struct {
+	Name         string   `json:"name"`
+	WorkflowName string   `json:"workflow_name,omitempty"`
+	PRs          []string `json:"prs"`
+	Since        string   `json:"since,omitempty"`
+}
+
+// DuplicateGroup is near-identical feedback left by one reviewer on the same
+// path across several PRs.
+type DuplicateGroup struct {
+	Author   string          `json:"author"`
+	Path     string          `json:"path,omitempty"`
+	Body     string          `json:"body"`
+	PRs      []string        `json:"prs"`
+	Comments []ReviewComment `json:"comments"`
+}
+
+// maxConcurrentFetches bounds how many PRs are fetched at once
+const maxConcurrentFetches = 4
+
+// duplicateSimilarity is the minimum word overlap for two comments to be
+// considered the same feedback.
+const duplicateSimilarity = 0.7
+
+// runMultiPR fetches feedback for every PR selected by --mine, --stack or
+// --org and prints a combined summary.
+func runMultiPR(opts *options) {
+	client := newClient(opts)
+
+	if opts.repoName == "" {
+		if repo, err := getCurrentRepo(); err == nil {
+			opts.repoName = repo
+		}
+	}
+
+	var refs []prRef
+	var err error
+	if opts.stack {
+		if opts.prNumber == 0 {
+			currentPR, currentRepo, err := getCurrentPR(client)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			opts.prNumber = currentPR
+			opts.repoName = currentRepo
+		}
+		if opts.repoName == "" {
+			fmt.Fprintf(os.Stderr, "Error: --stack needs a repository; use --repo owner/name\n")
+			os.Exit(1)
+		}
+		refs, err = listStackPRs(client, opts.repoName, opts.prNumber)
+	} else if opts.org != "" {
+		refs, err = searchPRs(client, "is:pr is:open org:"+opts.org)
+	} else if opts.base != "" {
+		if opts.repoName == "" {
+			fmt.Fprintf(os.Stderr, "Error: --base needs a repository; use --repo owner/name\n")
+			os.Exit(1)
+		}
+		refs, err = listBasePRs(client, opts.repoName, opts.base)
+	} else {
+		refs, err = listMyPRs(client, opts.repoName)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing pull requests: %v\n", err)
+		os.Exit(1)
+	}
+
+	if opts.estimate {
+		estimateScan(len(refs))
+	}
+
+	result := &MultiFeedback{PullRequests: fetchAllFeedback(client, opts, refs)}
+	for _, feedback := range result.PullRequests {
+		filterFeedback(feedback, opts)
+		if opts.sort != "" {
+			sortFeedback(feedback, opts.sort)
+		}
+		filterAnnotationPaths(feedback, opts.paths)
+		filterScope(feedback, opts.scope)
+	}
+
+	weights := defaultScoreWeights
+	if config, err := loadConfig(client, opts.repoName); err != nil {
+		if !budgetSkipped(err) {
+			warnf("%v", err)
+		}
+	} else {
+		weights = config.Score
+	}
+	// Rank the PRs needing the most attention first
+	for _, feedback := range result.PullRequests {
+		feedback.Score = scoreFeedback(feedback, weights)
+		limitFeedback(feedback, opts.limit)
+	}
+	if opts.retention > 0 {
+		for _, feedback := range result.PullRequests {
+			applyRetention(feedback, opts.retention)
+		}
+	}
+	sort.SliceStable(result.PullRequests, func(i, j int) bool {
+		return result.PullRequests[i].Score.Total > result.PullRequests[j].Score.Total
+	})
+	result.Duplicates = findDuplicates(result.PullRequests)
+	result.CheckClusters = clusterFailingChecks(result.PullRequests)
+
+	if opts.jsonOutput {
+		printJSON(opts, result)
+	} else {
+		printMultiFeedback(result)
+	}
+}
+
+// listMyPRs returns the authenticated user's open PRs, limited to repo when
+// one is given.
+func listMyPRs(client *api.RESTClient, repo string) ([]prRef, error) {
+	query := "is:pr is:open author:@me"
+	if repo != "" {
+		query += " repo:" + repo
+	}
+	return searchPRs(client, query)
+}
+
+// listBasePRs returns the open PRs whose base branch matches a glob such as
+// release/*, to see what's blocking a release
+func listBasePRs(client *api.RESTClient, repo, base string) ([]prRef, error) {
+	pulls, err := getAllPages[pullBranches](client, fmt.Sprintf("repos/%s/pulls?state=open", repo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	var refs []prRef
+	for _, pull := range pulls {
+		if globMatch(base, pull.Base.Ref) {
+			refs = append(refs, prRef{Repo: repo, Number: pull.Number})
+		}
+	}
+	return refs, nil
+}
+
+// errSearchLimit stops a search at the 1000 results GitHub returns at most
+var errSearchLimit = errors.New("search result limit reached")
+
+// searchPRs returns the PRs matching an issue search query
+func searchPRs(client *api.RESTClient, query string) ([]prRef, error) {
+	type item struct {
+		Number        int    `json:"number"`
+		RepositoryURL string `json:"repository_url"`
+	}
+	var refs []prRef
+	endpoint := fmt.Sprintf("search/issues?q=%s", url.QueryEscape(query))
+	err := eachFieldPage(client, endpoint, "items", func(items []item) error {
+		for _, item := range items {
+			refs = append(refs, prRef{Repo: repoFromURL(item.RepositoryURL), Number: item.Number})
+		}
+		if len(refs) >= 1000 {
+			return errSearchLimit
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSearchLimit) {
+		return nil, fmt.Errorf("failed to search pull requests: %w", err)
+	}
+	return refs, nil
+}
+
+// repoFromURL extracts owner/name from an API repository URL
+func repoFromURL(repositoryURL string) string {
+	parts := strings.Split(strings.TrimSuffix(repositoryURL, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+}
+
+type pullBranches struct {
+	Number int `json:"number"`
+	Head   struct {
+		Ref  string `json:"ref"`
+		Repo struct {
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repo"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// listStackPRs returns the open PRs stacked with prNumber: the chain of PRs
+// it is based on, followed by the PR itself and those based on top of it.
+func listStackPRs(client *api.RESTClient, repo string, prNumber int) ([]prRef, error) {
+	var pr pullBranches
+	if err := client.Get(fmt.Sprintf("repos/%s/pulls/%d", repo, prNumber), &pr); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR details: %w", err)
+	}
+	owner := strings.Split(repo, "/")[0]
+	seen := map[int]bool{pr.Number: true}
+
+	// Walk down to the PRs whose head branch this one targets
+	var below []prRef
+	base := pr.Base.Ref
+	for {
+		var pulls []pullBranches
+		endpoint := fmt.Sprintf("repos/%s/pulls?state=open&head=%s", repo, url.QueryEscape(owner+":"+base))
+		if err := client.Get(endpoint, &pulls); err != nil {
+			return nil, fmt.Errorf("failed to list stacked PRs: %w", err)
+		}
+		if len(pulls) == 0 || seen[pulls[0].Number] {
+			break
+		}
+		seen[pulls[0].Number] = true
+		below = append([]prRef{{Repo: repo, Number: pulls[0].Number}}, below...)
+		base = pulls[0].Base.Ref
+	}
+
+	refs := append(below, prRef{Repo: repo, Number: pr.Number})
+
+	// Walk up to every PR targeting a branch in the stack
+	heads := []string{pr.Head.Ref}
+	for len(heads) > 0 {
+		head := heads[0]
+		heads = heads[1:]
+
+		var pulls []pullBranches
+		endpoint := fmt.Sprintf("repos/%s/pulls?state=open&base=%s&per_page=100", repo, url.QueryEscape(head))
+		if err := client.Get(endpoint, &pulls); err != nil {
+			return nil, fmt.Errorf("failed to list stacked PRs: %w", err)
+		}
+		for _, pull := range pulls {
+			if seen[pull.Number] {
+				continue
+			}
+			seen[pull.Number] = true
+			refs = append(refs, prRef{Repo: repo, Number: pull.Number})
+			heads = append(heads, pull.Head.Ref)
+		}
+	}
+
+	return refs, nil
+}
+
+// fetchAllFeedback fetches feedback for each PR concurrently, preserving the
+// order of refs. PRs that fail to load are reported and skipped. Comments are
+// fetched for every PR before any checks, so a --max-requests budget runs out
+// on the less important data first.
+func fetchAllFeedback(client *api.RESTClient, opts *options, refs []prRef) []*PRFeedback {
+	results := make([]*PRFeedback, len(refs))
+	forEachPR(refs, func(i int, ref prRef) {
+		feedback, err := getPRComments(client, opts, ref.Repo, ref.Number)
+		if err != nil {
+			if !budgetSkipped(err) {
+				warnf("failed to fetch %s#%d: %v", ref.Repo, ref.Number, err)
+			}
+			return
+		}
+		results[i] = feedback
+	})
+	forEachPR(refs, func(i int, ref prRef) {
+		if results[i] != nil {
+			attachStatusChecks(client, results[i])
+		}
+	})
+
+	var feedbacks []*PRFeedback
+	for _, feedback := range results {
+		if feedback != nil {
+			feedbacks = append(feedbacks, feedback)
+		}
+	}
+	return feedbacks
+}
+
+// forEachPR calls fn for every PR, running up to maxConcurrentFetches at once
+func forEachPR(refs []prRef, fn func(i int, ref prRef)) {
+	sem := make(chan struct{}, maxConcurrentFetches)
+	var wg sync.WaitGroup
+
+	for i, ref := range refs {
+		wg.Add(1)
+		go func(i int, ref prRef) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(i, ref)
+		}(i, ref)
+	}
+	wg.Wait()
+}
+
+// findDuplicates groups line comments left by the same reviewer on the same
+// path with near-identical bodies, keeping only groups spanning several PRs.
+func findDuplicates(feedbacks []*PRFeedback) []DuplicateGroup {
+	type candidate struct {
+		group DuplicateGroup
+		words map[string]bool
+	}
+	var candidates []*candidate
+
+	for _, feedback := range feedbacks {
+		for _, comment := range feedback.Comments {
+			words := wordSet(comment.Body)
+			if len(words) == 0 {
+				continue
+			}
+
+			var match *candidate
+			for _, c := range candidates {
+				if c.group.Author == comment.Author && c.group.Path == comment.Path && jaccard(c.words, words) >= duplicateSimilarity {
+					match = c
+					break
+				}
+			}
+			if match == nil {
+				match = &candidate{
+					group: DuplicateGroup{Author: comment.Author, Path: comment.Path, Body: comment.Body},
+					words: words,
+				}
+				candidates = append(candidates, match)
+			}
+
+			match.group.Comments = append(match.group.Comments, comment)
+			ref := fmt.Sprintf("%s#%d", feedback.Repo, feedback.PRNumber)
+			if !containsFold(match.group.PRs, ref) {
+				match.group.PRs = append(match.group.PRs, ref)
+			}
+		}
+	}
+
+	var groups []DuplicateGroup
+	for _, c := range candidates {
+		if len(c.group.PRs) > 1 {
+			sort.Strings(c.group.PRs)
+			groups = append(groups, c.group)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].PRs) > len(groups[j].PRs)
+	})
+	return groups
+}
+
+// clusterFailingChecks groups failing checks by workflow and name, keeping
+// those failing on more than one PR, most widespread first.
+func clusterFailingChecks(feedbacks []*PRFeedback) []CheckCluster {
+	type key struct{ workflow, name string }
+	clusters := make(map[key]*CheckCluster)
+	var order []key
+
+	for _, feedback := range feedbacks {
+		for _, check := range feedback.StatusChecks {
+			k := key{check.WorkflowName, check.Name}
+			cluster, ok := clusters[k]
+			if !ok {
+				cluster = &CheckCluster{Name: check.Name, WorkflowName: check.WorkflowName}
+				clusters[k] = cluster
+				order = append(order, k)
+			}
+
+			ref := fmt.Sprintf("%s#%d", feedback.Repo, feedback.PRNumber)
+			if !containsFold(cluster.PRs, ref) {
+				cluster.PRs = append(cluster.PRs, ref)
+			}
+
+			// Track when the earliest of these failures happened
+			failedAt := check.CompletedAt
+			if failedAt == "" {
+				failedAt = check.StartedAt
+			}
+			if failedAt != "" && (cluster.Since == "" || failedAt < cluster.Since) {
+				cluster.Since = failedAt
+			}
+		}
+	}
+
+	var result []CheckCluster
+	for _, k := range order {
+		if len(clusters[k].PRs) > 1 {
+			sort.Strings(clusters[k].PRs)
+			result = append(result, *clusters[k])
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i].PRs) > len(result[j].PRs)
+	})
+	return result
+}
+
+// formatSince renders a timestamp compactly: just the time for today,
+// otherwise the date as well.
+func formatSince(timestamp string) string {
+	t, err := parseTime(timestamp)
+	if err != nil {
+		return ""
+	}
+	t = t.Local()
+	if today := now(); t.YearDay() == today.YearDay() && t.Year() == today.Year() {
+		return t.Format("15:04")
+	}
+	return t.Format("Jan 2 15:04")
+}
+
+// wordSet returns the set of lower-cased words in a comment body
+func wordSet(body string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_'
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// jaccard returns the similarity of two word sets between 0 and 1
+func jaccard(a, b map[string]bool) float64 {
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	total := len(a) + len(b) - shared
+	if total == 0 {
+		return 0
+	}
+	return float64(shared) / float64(total)
+}
+
+func printMultiFeedback(result *MultiFeedback) {
+	if len(result.PullRequests) == 0 {
+		fmt.Println("No open pull requests found")
+		return
+	}
+
+	for _, feedback := range result.PullRequests {
+		commentCount := len(feedback.Comments) + len(feedback.GeneralIssues)
+		checkCount := len(feedback.StatusChecks)
+
+		symbol := colorGreen + "✓" + colorReset
+		if checkCount > 0 {
+			symbol = colorRed + "X" + colorReset
+		} else if commentCount > 0 {
+			symbol = colorYellow + "!" + colorReset
+		}
+
+		location := feedback.Repo
+		if feedback.BaseRef != "" {
+			location += " → " + feedback.BaseRef
+		}
+		fmt.Printf("%s %s%s #%d%s %s(%s)%s\n", symbol, colorBold, feedback.Title, feedback.PRNumber, colorReset, colorGray, location, colorReset)
+		fmt.Printf("  score %d • %d unresolved comment(s), %d failing check(s) • %s%s%s\n", feedback.Score.Total, commentCount, checkCount, colorGray, feedback.URL, colorReset)
+	}
+
+	if len(result.CheckClusters) > 0 {
+		fmt.Println("\n" + strings.Repeat("─", separatorWidth()) + "\n")
+		fmt.Printf("%sShared Check Failures%s\n\n", colorBold, colorReset)
+		for _, cluster := range result.CheckClusters {
+			name := cluster.Name
+			if cluster.WorkflowName != "" && cluster.WorkflowName != cluster.Name {
+				name = cluster.WorkflowName + " / " + cluster.Name
+			}
+			fmt.Printf("%s✗%s %s failing on %d PRs", colorRed, colorReset, name, len(cluster.PRs))
+			if since := formatSince(cluster.Since); since != "" {
+				fmt.Printf(" since %s", since)
+			}
+			fmt.Println()
+			fmt.Printf("  %s%s%s\n", colorGray, strings.Join(cluster.PRs, ", "), colorReset)
+		}
+	}
+
+	if len(result.Duplicates) == 0 {
+		return
+	}
+
+	fmt.Println("\n" + strings.Repeat("─", separatorWidth()) + "\n")
+	fmt.Printf("%sRepeated Feedback%s\n\n", colorBold, colorReset)
+	for _, group := range result.Duplicates {
+		fmt.Printf("%s!%s Same feedback on %d PRs %s(%s)%s\n", colorYellow, colorReset, len(group.PRs), colorGray, strings.Join(group.PRs, ", "), colorReset)
+		fmt.Printf("  %s%s%s", colorBold+authorColor(group.Author), group.Author, colorReset)
+		if group.Path != "" {
+			fmt.Printf(" on %s%s%s", colorBlue, group.Path, colorReset)
+		}
+		fmt.Println()
+		firstLine := strings.SplitN(strings.TrimSpace(group.Body), "\n", 2)[0]
+		fmt.Printf("  %s\n\n", firstLine)
+	}
+}
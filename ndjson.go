@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// streamRecord is one line of --format ndjson output: a failing check, a
+// line comment thread or a general comment
+type streamRecord struct {
+	// Type is check, comment or general_comment
+	Type     string         `json:"type"`
+	Repo     string         `json:"repo"`
+	PRNumber int            `json:"pr_number"`
+	Comment  *ReviewComment `json:"comment,omitempty"`
+	Check    *StatusCheck   `json:"check,omitempty"`
+}
+
+// runNDJSON writes one JSON object per check and comment, each as soon as
+// its part of the PR is fetched, so consumers can start before a large PR
+// has loaded and nothing has to be held as one document. On GitHub the
+// checks come first, as they're a single request, then the comments page by
+// page. --sort needs every comment first, so it buffers them.
+func runNDJSON(provider Provider, opts *options) {
+	encoder := json.NewEncoder(os.Stdout)
+	emit := func(record streamRecord) {
+		record.Repo, record.PRNumber = opts.repoName, opts.prNumber
+		if err := encoder.Encode(record); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	emitChecks := func(checks []StatusCheck) {
+		classifyChecks(checks)
+		for _, check := range checks {
+			emit(streamRecord{Type: "check", Check: &check})
+		}
+	}
+	emitComments := func(feedback *PRFeedback) {
+		filterFeedback(feedback, opts)
+		if opts.sort != "" {
+			sortFeedback(feedback, opts.sort)
+		}
+		for _, comment := range feedback.GeneralIssues {
+			emit(streamRecord{Type: "general_comment", Comment: &comment})
+		}
+		for _, comment := range feedback.Comments {
+			emit(streamRecord{Type: "comment", Comment: &comment})
+		}
+	}
+
+	github, ok := provider.(*githubProvider)
+	if !ok || opts.shallow {
+		feedback, _, err := fetchFeedback(provider, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+			os.Exit(1)
+		}
+		checks := feedback.StatusChecks
+		feedback.StatusChecks = nil
+		if opts.only != "comments" {
+			emitChecks(checks)
+		}
+		emitComments(feedback)
+		return
+	}
+
+	if opts.only != "comments" {
+		checks, err := getStatusChecks(opts.repoName, opts.prNumber)
+		if err != nil && !budgetSkipped(err) {
+			warnf("failed to fetch status checks: %v", err)
+		}
+		emitChecks(checks)
+	}
+	if opts.only == "checks" {
+		return
+	}
+	if opts.sort != "" {
+		feedback, err := getPRComments(github.client, opts, opts.repoName, opts.prNumber)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+			os.Exit(1)
+		}
+		emitComments(feedback)
+		return
+	}
+	if err := streamPRComments(github.client, opts, emitComments); err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// streamPRComments fetches the PR's comments page by page, passing each
+// batch to emit as soon as it's complete: general comments a page at a time,
+// and review threads once all of their comments have been fetched, which the
+// thread sizes from GraphQL tell. Without them every thread waits for the
+// last page.
+func streamPRComments(client *api.RESTClient, opts *options, emit func(*PRFeedback)) error {
+	details, err := fetchPRDetails(client, opts.repoName, opts.prNumber)
+	if err != nil {
+		return err
+	}
+	batch := func() *PRFeedback {
+		return &PRFeedback{Repo: details.Repo, PRNumber: details.PRNumber, URL: details.URL}
+	}
+
+	// Reviews come first as the comments of your own pending review, which
+	// the comment list includes, aren't feedback yet
+	reviews := batch()
+	if err := attachReviews(client, reviews); err != nil {
+		return err
+	}
+	drafts := make(map[int]bool)
+	for _, comment := range reviews.Pending {
+		drafts[comment.ID] = true
+	}
+	measureThreads(reviews)
+	emit(reviews)
+
+	err = eachIssueCommentPage(client, details, func(page []ReviewComment) error {
+		general := batch()
+		general.GeneralIssues = page
+		markMinimizedComments(general, opts.showMinimized)
+		measureThreads(general)
+		emit(general)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sizes := make(map[int]int)
+	resolved := make(map[int]bool)
+	for _, thread := range lookupReviewThreads(opts, opts.repoName, opts.prNumber) {
+		sizes[thread.Comment.ID] = thread.Comments
+		resolved[thread.Comment.ID] = thread.IsResolved
+	}
+	var open []*ReviewComment
+	flush := func(all bool) {
+		threads := batch()
+		kept := open[:0]
+		for _, comment := range open {
+			if !all && (sizes[comment.ID] == 0 || 1+len(comment.Replies) < sizes[comment.ID]) {
+				kept = append(kept, comment)
+				continue
+			}
+			if resolved[comment.ID] {
+				if !opts.includeResolved {
+					continue
+				}
+				comment.State = "resolved"
+			}
+			threads.Comments = append(threads.Comments, *comment)
+		}
+		open = kept
+		if len(threads.Comments) == 0 {
+			return
+		}
+		markMinimizedComments(threads, opts.showMinimized)
+		measureThreads(threads)
+		emit(threads)
+	}
+
+	endpoint := fmt.Sprintf("repos/%s/pulls/%d/comments", opts.repoName, opts.prNumber)
+	err = eachReviewCommentPage(client, endpoint, func(page []ReviewComment) error {
+		for _, comment := range page {
+			if drafts[comment.ID] {
+				continue
+			}
+			comment.DiscussionURL = discussionURL(details.URL, comment)
+			if comment.InReplyTo == nil {
+				started := comment
+				open = append(open, &started)
+				continue
+			}
+			// Replies always follow the comment they reply to
+			for _, thread := range open {
+				if thread.ID == *comment.InReplyTo {
+					thread.Replies = append(thread.Replies, comment)
+					break
+				}
+			}
+		}
+		flush(false)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch review comments: %w", err)
+	}
+	flush(true)
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import "fmt"
+
+// filterOutdated drops outdated threads, or with mode "only" everything else,
+// including general comments, which are never outdated
+func filterOutdated(feedback *PRFeedback, mode string) {
+	if mode == "" {
+		return
+	}
+	kept := feedback.Comments[:0]
+	for _, comment := range feedback.Comments {
+		if comment.Outdated == (mode == "only") {
+			kept = append(kept, comment)
+		}
+	}
+	feedback.Comments = kept
+	if mode == "only" {
+		feedback.GeneralIssues = nil
+	}
+}
+
+// splitOutdated separates the outdated threads, which are shown collapsed
+// unless --hide-outdated or --only-outdated set a mode
+func splitOutdated(comments []ReviewComment, mode string) (current, outdated []ReviewComment) {
+	if mode != "" {
+		return comments, nil
+	}
+	for _, comment := range comments {
+		if comment.Outdated {
+			outdated = append(outdated, comment)
+		} else {
+			current = append(current, comment)
+		}
+	}
+	return current, outdated
+}
+
+// printOutdatedSummary lists outdated threads in one line each
+func printOutdatedSummary(comments []ReviewComment, separator string) {
+	fmt.Println("\n" + separator + "\n")
+	fmt.Printf("%s%d outdated thread(s)%s %s(--only-outdated to expand)%s\n", colorBold, len(comments), colorReset, colorGray, colorReset)
+	for _, comment := range comments {
+		location := comment.Path
+		if comment.OriginalLine != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.OriginalLine)
+		}
+		fmt.Printf("  %s%s%s %s%s: %s%s\n", colorBlue, location, colorReset, colorGray, comment.Author, firstLine(comment.Body), colorReset)
+	}
+}
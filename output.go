@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// outputWriters render the feedback for --out, keyed by format name
+var outputWriters = map[string]func(w io.Writer, opts *options, feedback *PRFeedback) error{
+	"actions": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		writeActionsAnnotations(w, feedback)
+		return nil
+	},
+	"csv": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		return writeFeedbackTable(w, feedback, ',')
+	},
+	"json": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		return writeJSON(w, opts, feedback)
+	},
+	"html": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		return writeHTML(w, feedback)
+	},
+	"junit": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		return writeJUnit(w, feedback)
+	},
+	"markdown": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		writeMarkdown(w, feedback)
+		return nil
+	},
+	"prompt": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		writePrompt(w, feedback)
+		return nil
+	},
+	"quickfix": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		writeQuickfix(w, feedback)
+		return nil
+	},
+	"rdjson": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		return writeRDJSON(w, feedback)
+	},
+	"sarif": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		return writeSARIF(w, feedback)
+	},
+	"tsv": func(w io.Writer, opts *options, feedback *PRFeedback) error {
+		return writeFeedbackTable(w, feedback, '\t')
+	},
+}
+
+// outputFile is a file written by --out in addition to the terminal output
+type outputFile struct {
+	Format string
+	Path   string
+}
+
+// parseOutputFile parses a --out value such as json=feedback.json
+func parseOutputFile(value string) (outputFile, error) {
+	format, path, ok := strings.Cut(value, "=")
+	if !ok || path == "" {
+		return outputFile{}, fmt.Errorf("--out expects format=file, e.g. json=feedback.json")
+	}
+	if _, ok := outputWriters[format]; !ok {
+		var formats []string
+		for name := range outputWriters {
+			formats = append(formats, name)
+		}
+		sort.Strings(formats)
+		return outputFile{}, fmt.Errorf("unknown --out format %q (expected %s)", format, strings.Join(formats, ", "))
+	}
+	return outputFile{Format: format, Path: path}, nil
+}
+
+// writeOutputFiles writes every --out file from the same fetch
+func writeOutputFiles(opts *options, feedback *PRFeedback) error {
+	for _, output := range opts.outputs {
+		file, err := os.Create(output.Path)
+		if err != nil {
+			return err
+		}
+		err = outputWriters[output.Format](file, opts, feedback)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", output.Path, err)
+		}
+	}
+	return nil
+}
+
+// printMarkdown prints the feedback as Markdown, for --format markdown
+func printMarkdown(feedback *PRFeedback) {
+	writeMarkdown(os.Stdout, feedback)
+}
+
+// writeMarkdown renders the feedback as a Markdown document, with failing
+// checks as a checklist and line comments grouped under the file they're on,
+// for pasting into issues, docs or a prompt
+func writeMarkdown(w io.Writer, feedback *PRFeedback) {
+	fmt.Fprintf(w, "# %s #%d\n\n", feedback.Title, feedback.PRNumber)
+	fmt.Fprintf(w, "%s\n", feedback.URL)
+	if feedback.ReviewDecision != "" {
+		fmt.Fprintf(w, "\nReview decision: %s\n", strings.ToLower(strings.ReplaceAll(feedback.ReviewDecision, "_", " ")))
+	}
+	if feedback.Summary != "" {
+		fmt.Fprintf(w, "\n## Summary\n\n%s\n", feedback.Summary)
+	}
+
+	if len(feedback.StatusChecks) > 0 {
+		fmt.Fprintf(w, "\n## Failing checks\n\n")
+		for _, check := range feedback.StatusChecks {
+			name := check.Name
+			if check.DetailsURL != "" {
+				name = fmt.Sprintf("[%s](%s)", check.Name, check.DetailsURL)
+			}
+			required := ""
+			if check.Required {
+				required = ", required"
+			}
+			fmt.Fprintf(w, "- [ ] %s: %s%s\n", name, strings.ToLower(check.Conclusion), required)
+			for _, annotation := range check.Annotations {
+				fmt.Fprintf(w, "  - `%s:%d`: %s\n", annotation.Path, annotation.StartLine, firstLine(annotation.Message))
+			}
+		}
+	}
+
+	if len(feedback.GeneralIssues) > 0 {
+		fmt.Fprintf(w, "\n## General comments\n")
+		for _, comment := range feedback.GeneralIssues {
+			writeMarkdownComment(w, comment)
+		}
+	}
+
+	comments := append([]ReviewComment{}, feedback.Comments...)
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].Path < comments[j].Path })
+	path := ""
+	for _, comment := range comments {
+		if comment.Generated {
+			continue
+		}
+		if comment.Path != path {
+			path = comment.Path
+			fmt.Fprintf(w, "\n## %s\n", path)
+		}
+		writeMarkdownComment(w, comment)
+	}
+}
+
+func writeMarkdownComment(w io.Writer, comment ReviewComment) {
+	heading := "@" + comment.Author
+	if comment.Line != nil {
+		heading = fmt.Sprintf("Line %d, @%s", *comment.Line, comment.Author)
+	}
+	if comment.DiscussionURL != "" {
+		heading = fmt.Sprintf("[%s](%s)", heading, comment.DiscussionURL)
+	}
+	if comment.Outdated {
+		heading += " (outdated)"
+	}
+	if comment.State == "resolved" {
+		heading += " (resolved)"
+	}
+	if comment.AssignedTo != "" {
+		heading += " (assigned to @" + comment.AssignedTo + ")"
+	}
+	fmt.Fprintf(w, "\n### %s\n", heading)
+	if comment.DiffHunk != "" {
+		fmt.Fprintf(w, "\n%s\n", fenceDiff(comment.DiffHunk))
+	}
+	fmt.Fprintln(w)
+	for _, line := range strings.Split(strings.TrimSpace(comment.Body), "\n") {
+		fmt.Fprintf(w, "> %s\n", line)
+	}
+	for _, reply := range comment.Replies {
+		fmt.Fprintf(w, "\n> **@%s** replied:\n>\n", reply.Author)
+		for _, line := range strings.Split(strings.TrimSpace(reply.Body), "\n") {
+			fmt.Fprintf(w, "> %s\n", line)
+		}
+	}
+	if comment.Note != "" {
+		fmt.Fprintf(w, "\n_Note: %s_\n", comment.Note)
+	}
+	writeMarkdownDocs(w, comment.Docs)
+}
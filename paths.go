@@ -0,0 +1,44 @@
+package main
+
+// matchesAnyPath reports whether path matches one of globs
+func matchesAnyPath(globs []string, path string) bool {
+	for _, glob := range globs {
+		if path != "" && globMatch(glob, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterPaths keeps only the line comments on files matching one of globs,
+// set by --path. General comments aren't on any file, so they're dropped too.
+func filterPaths(feedback *PRFeedback, globs []string) {
+	if len(globs) == 0 {
+		return
+	}
+	kept := feedback.Comments[:0]
+	for _, comment := range feedback.Comments {
+		if matchesAnyPath(globs, comment.Path) {
+			kept = append(kept, comment)
+		}
+	}
+	feedback.Comments = kept
+	feedback.GeneralIssues = nil
+}
+
+// filterAnnotationPaths keeps only the check annotations on files matching
+// one of globs. The checks themselves are kept, since they fail either way.
+func filterAnnotationPaths(feedback *PRFeedback, globs []string) {
+	if len(globs) == 0 {
+		return
+	}
+	for i, check := range feedback.StatusChecks {
+		var kept []CheckAnnotation
+		for _, annotation := range check.Annotations {
+			if matchesAnyPath(globs, annotation.Path) {
+				kept = append(kept, annotation)
+			}
+		}
+		feedback.StatusChecks[i].Annotations = kept
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// attachPendingReview adds the comments of an unsubmitted review to the
+// feedback. GitHub only lists pending reviews to their author, so these are
+// always the authenticated user's own drafts.
+func attachPendingReview(client *api.RESTClient, feedback *PRFeedback, reviewID int, body string) {
+	endpoint := fmt.Sprintf("repos/%s/pulls/%d/reviews/%d/comments", feedback.Repo, feedback.PRNumber, reviewID)
+	comments, err := listReviewComments(client, endpoint)
+	if err != nil {
+		if !budgetSkipped(err) {
+			warnf("failed to fetch pending review comments: %v", err)
+		}
+		return
+	}
+	if body != "" {
+		feedback.Pending = append(feedback.Pending, ReviewComment{ID: reviewID, Body: body, State: "pending"})
+	}
+
+	pending := make(map[int]bool)
+	for _, comment := range comments {
+		comment.State = "pending"
+		feedback.Pending = append(feedback.Pending, comment)
+		pending[comment.ID] = true
+	}
+
+	// Drafts can also show up in the PR's comment list, but they aren't
+	// feedback anyone has given yet
+	kept := feedback.Comments[:0]
+	for _, comment := range feedback.Comments {
+		if pending[comment.ID] {
+			continue
+		}
+		replies := comment.Replies[:0]
+		for _, reply := range comment.Replies {
+			if !pending[reply.ID] {
+				replies = append(replies, reply)
+			}
+		}
+		comment.Replies = replies
+		kept = append(kept, comment)
+	}
+	feedback.Comments = kept
+}
+
+// printPendingReview lists your unsubmitted review comments in their own
+// section, as a reminder to submit them
+func printPendingReview(pending []ReviewComment, width int, separator string) {
+	if len(pending) == 0 {
+		return
+	}
+	fmt.Println("\n" + separator + "\n")
+	fmt.Printf("%sPending (unsubmitted)%s\n", colorBold, colorReset)
+	for _, comment := range pending {
+		fmt.Println()
+		switch {
+		case comment.Path == "":
+			fmt.Printf("%sReview summary%s\n", colorBlue, colorReset)
+		case comment.Line != nil && *comment.Line > 0:
+			fmt.Printf("%s%s on line %d%s", colorBlue, comment.Path, *comment.Line, colorReset)
+		default:
+			fmt.Printf("%s%s%s", colorBlue, comment.Path, colorReset)
+		}
+		if comment.Path != "" {
+			if comment.InReplyTo != nil {
+				fmt.Printf(" %s• reply%s", colorGray, colorReset)
+			}
+			fmt.Println()
+		}
+		printBody(comment.Body, width)
+	}
+}
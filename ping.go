@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultPingTemplate is the nudge posted by `ping`, overridable with
+// ping_template in the repository config
+const defaultPingTemplate = `Hi {{.Mention}} 👋 just a gentle reminder that this PR has been waiting on your review for {{.Age}}. No rush if you're busy, and thanks!`
+
+// pingData is what ping templates are rendered with
+type pingData struct {
+	Mention string
+	Login   string
+	Age     string
+	Title   string
+	URL     string
+}
+
+// runPing posts a polite comment nudging a requested reviewer, mentioning
+// how long they've been waiting.
+func runPing(args []string) {
+	var login, message string
+	var dryRun bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--message" || arg == "-m":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			message = args[i+1]
+			i++
+		case login == "" && !strings.HasPrefix(arg, "-") && strings.Trim(arg, "0123456789") != "":
+			login = strings.TrimPrefix(arg, "@")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if login == "" {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback ping <login|team> [-m template] [--dry-run] [pr-number]\n")
+		os.Exit(1)
+	}
+
+	opts := parseArgs(rest)
+	client := resolvePR(opts)
+
+	requests, err := fetchReviewRequests(client, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var request *ReviewRequest
+	for i := range requests {
+		if strings.EqualFold(requests[i].Reviewer, login) {
+			request = &requests[i]
+		}
+	}
+	if request == nil {
+		fmt.Fprintf(os.Stderr, "Error: %s has no pending review request on PR #%d\n", login, opts.prNumber)
+		os.Exit(1)
+	}
+
+	if message == "" {
+		message = defaultPingTemplate
+		if config, err := loadConfig(client, opts.repoName); err == nil && config.PingTemplate != "" {
+			message = config.PingTemplate
+		}
+	}
+	tmpl, err := parsePingTemplate(message)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid ping template: %v\n", err)
+		os.Exit(1)
+	}
+
+	feedback, err := fetchPRDetails(client, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR details: %v\n", err)
+		os.Exit(1)
+	}
+	data := pingData{
+		Mention: "@" + request.Reviewer,
+		Login:   request.Reviewer,
+		Age:     "a while",
+		Title:   feedback.Title,
+		URL:     feedback.URL,
+	}
+	if request.IsTeam {
+		owner, _, _ := strings.Cut(opts.repoName, "/")
+		data.Mention = "@" + owner + "/" + request.Reviewer
+	}
+	if t, err := parseTime(request.RequestedAt); err == nil {
+		data.Age = formatWaitTime(now().Sub(t))
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid ping template: %v\n", err)
+		os.Exit(1)
+	}
+
+	if dryRun {
+		fmt.Println(body.String())
+		return
+	}
+
+	payload, err := json.Marshal(map[string]string{"body": body.String()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	endpoint := fmt.Sprintf("repos/%s/issues/%d/comments", opts.repoName, opts.prNumber)
+	if err := client.Post(endpoint, bytes.NewReader(payload), nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error posting comment: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Pinged %s on PR #%d\n", data.Mention, opts.prNumber)
+}
+
+func parsePingTemplate(text string) (*template.Template, error) {
+	return template.New("ping").Parse(text)
+}
+
+// formatWaitTime describes a wait in words for comments, e.g. "3 days"
+func formatWaitTime(d time.Duration) string {
+	switch {
+	case d < 2*time.Hour:
+		return "about an hour"
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	}
+}
+
+// formatReviewRequests summarizes who the PR is waiting on, e.g.
+// "alice (3d), platform-team (7h)"
+func formatReviewRequests(requests []ReviewRequest) string {
+	var parts []string
+	for _, request := range requests {
+		part := request.Reviewer
+		if t, err := parseTime(request.RequestedAt); err == nil {
+			part += " (" + formatAge(now().Sub(t)) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
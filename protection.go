@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// markRequiredChecks flags the failing checks that the base branch's
+// protection rules or rulesets require, on top of what `gh pr checks
+// --required` reported, and lists them first: they're the ones blocking the
+// merge. Reading the rules is best-effort, as branches without protection
+// and tokens that can't see it both return errors.
+func markRequiredChecks(client *api.RESTClient, feedback *PRFeedback) {
+	if len(feedback.StatusChecks) == 0 {
+		return
+	}
+	if client != nil && feedback.BaseRef != "" {
+		required := protectedContexts(client, feedback.Repo, feedback.BaseRef)
+		for i, check := range feedback.StatusChecks {
+			if required[check.Name] {
+				feedback.StatusChecks[i].Required = true
+			}
+		}
+	}
+	sortRequiredFirst(feedback.StatusChecks)
+}
+
+// protectedContexts returns the status check contexts required on branch by
+// classic branch protection and by any repository or organization rulesets
+func protectedContexts(client *api.RESTClient, repo, branch string) map[string]bool {
+	required := make(map[string]bool)
+
+	// The branch endpoint exposes its protection to anyone who can read the
+	// repository, unlike the protection endpoint itself
+	var protected struct {
+		Protection struct {
+			RequiredStatusChecks struct {
+				Contexts []string `json:"contexts"`
+				Checks   []struct {
+					Context string `json:"context"`
+				} `json:"checks"`
+			} `json:"required_status_checks"`
+		} `json:"protection"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/branches/%s", repo, branch), &protected); err == nil {
+		for _, context := range protected.Protection.RequiredStatusChecks.Contexts {
+			required[context] = true
+		}
+		for _, check := range protected.Protection.RequiredStatusChecks.Checks {
+			required[check.Context] = true
+		}
+	}
+
+	var rules []struct {
+		Type       string `json:"type"`
+		Parameters struct {
+			RequiredStatusChecks []struct {
+				Context string `json:"context"`
+			} `json:"required_status_checks"`
+		} `json:"parameters"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/rules/branches/%s", repo, branch), &rules); err == nil {
+		for _, rule := range rules {
+			if rule.Type != "required_status_checks" {
+				continue
+			}
+			for _, check := range rule.Parameters.RequiredStatusChecks {
+				required[check.Context] = true
+			}
+		}
+	}
+	return required
+}
+
+// sortRequiredFirst moves required checks ahead of optional ones, keeping
+// the order within each
+func sortRequiredFirst(checks []StatusCheck) {
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].Required && !checks[j].Required
+	})
+}
+
+// formatCheckRequirement tags a failing check that blocks the merge
+func formatCheckRequirement(check StatusCheck) string {
+	if !check.Required {
+		return ""
+	}
+	return fmt.Sprintf(" %s• required%s", colorRed, colorReset)
+}
+
+// formatRequiredCount says how many of the failing checks block the merge,
+// and so how many are optional
+func formatRequiredCount(checks []StatusCheck) string {
+	required := 0
+	for _, check := range checks {
+		if check.Required {
+			required++
+		}
+	}
+	if required == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %s(%d required, %d optional)%s", colorGray, required, len(checks)-required, colorReset)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Provider fetches review feedback from a code hosting service. GitHub is the
+// default; other hosts are selected with --provider or by the git remote.
+type Provider interface {
+	// Name identifies the provider, e.g. "github"
+	Name() string
+	// CurrentRepo returns the repository for the current directory
+	CurrentRepo() (string, error)
+	// CurrentPR returns the open pull/merge request for the current branch
+	CurrentPR(repo string) (int, error)
+	// FetchFeedback returns the unresolved feedback on a pull/merge request
+	FetchFeedback(repo string, number int) (*PRFeedback, error)
+}
+
+type githubProvider struct {
+	client *api.RESTClient
+	opts   *options
+}
+
+func (p *githubProvider) Name() string { return "github" }
+
+func (p *githubProvider) CurrentRepo() (string, error) {
+	return getCurrentRepo()
+}
+
+func (p *githubProvider) CurrentPR(repo string) (int, error) {
+	number, _, err := getCurrentPR(p.client)
+	return number, err
+}
+
+func (p *githubProvider) FetchFeedback(repo string, number int) (*PRFeedback, error) {
+	return getPRFeedback(p.client, p.opts, repo, number)
+}
+
+// selectProvider picks the provider named by --provider, falling back to
+// detecting it from the origin remote's host.
+func selectProvider(opts *options) Provider {
+	changeDir(opts)
+
+	name := opts.provider
+	var remoteHost, remotePath string
+	if remote, err := originRemote(); err == nil {
+		remoteHost, remotePath = parseRemoteURL(remote)
+	}
+	if name == "" {
+		name = detectProvider(remoteHost)
+	}
+
+	switch name {
+	case "github":
+		return &githubProvider{client: createClient(), opts: opts}
+	case "gitlab":
+		return newGitLabProvider(remoteHost, remotePath)
+	case "bitbucket":
+		return newBitbucketProvider(remotePath)
+	case "gitea", "forgejo":
+		return newGiteaProvider(remoteHost, remotePath)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown provider %q\n", name)
+		os.Exit(1)
+	}
+	return nil
+}
+
+// detectProvider guesses the provider from a remote host name
+func detectProvider(host string) string {
+	host = strings.ToLower(host)
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return "gitlab"
+	case host == "bitbucket.org":
+		return "bitbucket"
+	case strings.Contains(host, "gitea"), strings.Contains(host, "forgejo"), host == "codeberg.org":
+		return "gitea"
+	default:
+		return "github"
+	}
+}
+
+// originRemote returns the URL of the origin remote
+func originRemote() (string, error) {
+	output, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", fmt.Errorf("no origin remote")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseRemoteURL splits a git remote URL into its host and repository path,
+// handling https://, ssh:// and scp-like git@host:path forms.
+func parseRemoteURL(remote string) (string, string) {
+	remote = strings.TrimSuffix(strings.TrimSpace(remote), ".git")
+
+	if !strings.Contains(remote, "://") {
+		// scp-like syntax: [user@]host:path
+		if i := strings.Index(remote, ":"); i > 0 {
+			host := remote[:i]
+			if at := strings.LastIndex(host, "@"); at >= 0 {
+				host = host[at+1:]
+			}
+			return host, strings.Trim(remote[i+1:], "/")
+		}
+		return "", ""
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil {
+		return "", ""
+	}
+	return u.Hostname(), strings.Trim(u.Path, "/")
+}
+
+// currentBranch returns the name of the checked out branch
+func currentBranch() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current branch")
+	}
+	branch := strings.TrimSpace(string(output))
+	if branch == "HEAD" {
+		return "", fmt.Errorf("not on a branch")
+	}
+	return branch, nil
+}
+
+// getJSON fetches a URL and decodes the JSON response into out, returning
+// the response headers for pagination.
+func getJSON(httpClient *http.Client, endpoint string, headers map[string]string, out interface{}) (http.Header, error) {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		// Hosts disagree on error shapes: GitLab and Gitea use "message",
+		// Bitbucket nests it under "error"
+		var body struct {
+			Message interface{} `json:"message"`
+			Error   interface{} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		var message string
+		switch e := body.Error.(type) {
+		case string:
+			message = e
+		case map[string]interface{}:
+			message = fmt.Sprint(e["message"])
+		}
+		if body.Message != nil {
+			message = fmt.Sprint(body.Message)
+		}
+		return nil, fmt.Errorf("HTTP %d: %s (%s)", resp.StatusCode, message, endpoint)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %w", endpoint, err)
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// bitbucketProvider reads pull request comments, tasks and build statuses
+// from Bitbucket Cloud. Credentials come from BITBUCKET_TOKEN, or
+// BITBUCKET_USERNAME with BITBUCKET_APP_PASSWORD.
+type bitbucketProvider struct {
+	remotePath string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+const bitbucketAPI = "https://api.bitbucket.org/2.0/"
+
+func newBitbucketProvider(remotePath string) *bitbucketProvider {
+	headers := map[string]string{}
+	if token := os.Getenv("BITBUCKET_TOKEN"); token != "" {
+		headers["Authorization"] = "Bearer " + token
+	} else if user := os.Getenv("BITBUCKET_USERNAME"); user != "" {
+		credentials := user + ":" + os.Getenv("BITBUCKET_APP_PASSWORD")
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+
+	return &bitbucketProvider{
+		remotePath: remotePath,
+		headers:    headers,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *bitbucketProvider) Name() string { return "bitbucket" }
+
+func (p *bitbucketProvider) CurrentRepo() (string, error) {
+	if p.remotePath == "" {
+		return "", fmt.Errorf("failed to determine repository from the origin remote")
+	}
+	return p.remotePath, nil
+}
+
+func (p *bitbucketProvider) CurrentPR(repo string) (int, error) {
+	branch, err := currentBranch()
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		Values []struct {
+			ID int `json:"id"`
+		} `json:"values"`
+	}
+	query := fmt.Sprintf(`source.branch.name="%s" AND state="OPEN"`, branch)
+	endpoint := fmt.Sprintf("%srepositories/%s/pullrequests?q=%s", bitbucketAPI, repo, url.QueryEscape(query))
+	if _, err := getJSON(p.httpClient, endpoint, p.headers, &result); err != nil {
+		return 0, fmt.Errorf("failed to find pull request: %w", err)
+	}
+	if len(result.Values) == 0 {
+		return 0, fmt.Errorf("no PR found for current branch")
+	}
+	return result.Values[0].ID, nil
+}
+
+func (p *bitbucketProvider) FetchFeedback(repo string, number int) (*PRFeedback, error) {
+	base := fmt.Sprintf("%srepositories/%s/pullrequests/%d", bitbucketAPI, repo, number)
+
+	var pr struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+		State string `json:"state"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+		MergeCommit *struct {
+			Hash string `json:"hash"`
+		} `json:"merge_commit"`
+		Author bitbucketUser `json:"author"`
+	}
+	if _, err := getJSON(p.httpClient, base, p.headers, &pr); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR details: %w", err)
+	}
+
+	feedback := &PRFeedback{
+		Repo:     repo,
+		PRNumber: pr.ID,
+		Title:    pr.Title,
+		Author:   pr.Author.login(),
+		URL:      pr.Links.HTML.Href,
+		State:    "open",
+	}
+	switch pr.State {
+	case "MERGED":
+		feedback.State = "merged"
+		if pr.MergeCommit != nil {
+			feedback.MergeCommitSHA = pr.MergeCommit.Hash
+		}
+	case "DECLINED", "SUPERSEDED":
+		feedback.State = "closed"
+	}
+
+	if err := p.fetchComments(base, feedback); err != nil {
+		return nil, err
+	}
+	if err := p.fetchTasks(base, feedback); err != nil {
+		return nil, err
+	}
+
+	checks, err := p.fetchStatuses(base)
+	if err != nil {
+		// Don't fail the whole operation if build statuses fail
+		warnf("failed to fetch build statuses: %v", err)
+	} else {
+		feedback.StatusChecks = checks
+	}
+
+	return feedback, nil
+}
+
+type bitbucketUser struct {
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+}
+
+func (u bitbucketUser) login() string {
+	if u.Nickname != "" {
+		return u.Nickname
+	}
+	return u.DisplayName
+}
+
+// fetchComments adds top-level comments that haven't been resolved or
+// deleted. Inline comments become line comments.
+func (p *bitbucketProvider) fetchComments(base string, feedback *PRFeedback) error {
+	next := base + "/comments?pagelen=100"
+	for next != "" {
+		var page struct {
+			Values []struct {
+				ID      int `json:"id"`
+				Content struct {
+					Raw string `json:"raw"`
+				} `json:"content"`
+				User      bitbucketUser `json:"user"`
+				CreatedOn string        `json:"created_on"`
+				UpdatedOn string        `json:"updated_on"`
+				Deleted   bool          `json:"deleted"`
+				Parent    *struct {
+					ID int `json:"id"`
+				} `json:"parent"`
+				Inline *struct {
+					Path string `json:"path"`
+					To   *int   `json:"to"`
+					From *int   `json:"from"`
+				} `json:"inline"`
+				Resolution *struct {
+					Type string `json:"type"`
+				} `json:"resolution"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if _, err := getJSON(p.httpClient, next, p.headers, &page); err != nil {
+			return fmt.Errorf("failed to fetch comments: %w", err)
+		}
+
+		for _, c := range page.Values {
+			if c.Deleted || c.Parent != nil || c.Resolution != nil {
+				continue
+			}
+
+			comment := ReviewComment{
+				ID:            c.ID,
+				Body:          c.Content.Raw,
+				Author:        c.User.login(),
+				State:         "unresolved",
+				CreatedAt:     c.CreatedOn,
+				UpdatedAt:     c.UpdatedOn,
+				DiscussionURL: fmt.Sprintf("%s#comment-%d", feedback.URL, c.ID),
+			}
+			if c.Inline == nil {
+				feedback.GeneralIssues = append(feedback.GeneralIssues, comment)
+				continue
+			}
+
+			comment.Path = c.Inline.Path
+			comment.Side = "RIGHT"
+			comment.Line = c.Inline.To
+			if comment.Line == nil && c.Inline.From != nil {
+				comment.Side = "LEFT"
+				comment.Line = c.Inline.From
+			}
+			comment.PositionState = commentPositionState("", comment.Line, nil)
+			feedback.Comments = append(feedback.Comments, comment)
+		}
+		next = page.Next
+	}
+	return nil
+}
+
+// fetchTasks adds unresolved PR tasks as general feedback
+func (p *bitbucketProvider) fetchTasks(base string, feedback *PRFeedback) error {
+	next := base + "/tasks?pagelen=100"
+	for next != "" {
+		var page struct {
+			Values []struct {
+				ID      int `json:"id"`
+				Content struct {
+					Raw string `json:"raw"`
+				} `json:"content"`
+				Creator   bitbucketUser `json:"creator"`
+				State     string        `json:"state"`
+				CreatedOn string        `json:"created_on"`
+				UpdatedOn string        `json:"updated_on"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if _, err := getJSON(p.httpClient, next, p.headers, &page); err != nil {
+			return fmt.Errorf("failed to fetch tasks: %w", err)
+		}
+
+		for _, task := range page.Values {
+			if task.State != "UNRESOLVED" {
+				continue
+			}
+			feedback.GeneralIssues = append(feedback.GeneralIssues, ReviewComment{
+				ID:          task.ID,
+				Body:        task.Content.Raw,
+				Author:      task.Creator.login(),
+				State:       "unresolved",
+				CreatedAt:   task.CreatedOn,
+				UpdatedAt:   task.UpdatedOn,
+				SubjectType: "task",
+			})
+		}
+		next = page.Next
+	}
+	return nil
+}
+
+// fetchStatuses returns failed and stopped build statuses as checks
+func (p *bitbucketProvider) fetchStatuses(base string) ([]StatusCheck, error) {
+	var checks []StatusCheck
+	next := base + "/statuses?pagelen=100"
+	for next != "" {
+		var page struct {
+			Values []struct {
+				Key       string `json:"key"`
+				Name      string `json:"name"`
+				State     string `json:"state"`
+				URL       string `json:"url"`
+				CreatedOn string `json:"created_on"`
+				UpdatedOn string `json:"updated_on"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if _, err := getJSON(p.httpClient, next, p.headers, &page); err != nil {
+			return nil, err
+		}
+
+		for _, status := range page.Values {
+			var conclusion string
+			switch status.State {
+			case "FAILED":
+				conclusion = "FAILURE"
+			case "STOPPED":
+				conclusion = "CANCELLED"
+			}
+			if !isFailingConclusion(conclusion) {
+				continue
+			}
+			name := status.Name
+			if name == "" {
+				name = status.Key
+			}
+			checks = append(checks, StatusCheck{
+				Name:        name,
+				Status:      "COMPLETED",
+				Conclusion:  conclusion,
+				DetailsURL:  status.URL,
+				StartedAt:   status.CreatedOn,
+				CompletedAt: status.UpdatedOn,
+			})
+		}
+		next = page.Next
+	}
+	return checks, nil
+}
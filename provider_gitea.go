@@ -0,0 +1,356 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// giteaProvider reads pull request reviews and commit statuses from Gitea
+// and Forgejo instances. The token comes from GITEA_TOKEN or FORGEJO_TOKEN.
+type giteaProvider struct {
+	host       string
+	remotePath string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+func newGiteaProvider(remoteHost, remotePath string) *giteaProvider {
+	host := os.Getenv("GITEA_HOST")
+	if host == "" {
+		host = remoteHost
+	}
+
+	token := os.Getenv("GITEA_TOKEN")
+	if token == "" {
+		token = os.Getenv("FORGEJO_TOKEN")
+	}
+	headers := map[string]string{}
+	if token != "" {
+		headers["Authorization"] = "token " + token
+	}
+
+	return &giteaProvider{
+		host:       host,
+		remotePath: remotePath,
+		headers:    headers,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *giteaProvider) Name() string { return "gitea" }
+
+func (p *giteaProvider) CurrentRepo() (string, error) {
+	if p.remotePath == "" {
+		return "", fmt.Errorf("failed to determine repository from the origin remote")
+	}
+	return p.remotePath, nil
+}
+
+func (p *giteaProvider) CurrentPR(repo string) (int, error) {
+	branch, err := currentBranch()
+	if err != nil {
+		return 0, err
+	}
+
+	type pull struct {
+		Number int `json:"number"`
+		Head   struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+	}
+	number := 0
+	err = giteaPages(p, fmt.Sprintf("repos/%s/pulls?state=open", repo), func(pulls []pull) error {
+		for _, pull := range pulls {
+			if pull.Head.Ref == branch {
+				number = pull.Number
+				return errFoundPR
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errFoundPR) {
+		return 0, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	if number == 0 {
+		return 0, fmt.Errorf("no PR found for current branch")
+	}
+	return number, nil
+}
+
+// errFoundPR stops listing pull requests once the branch's is found
+var errFoundPR = errors.New("found the branch's pull request")
+
+func (p *giteaProvider) FetchFeedback(repo string, number int) (*PRFeedback, error) {
+	if p.host == "" {
+		return nil, fmt.Errorf("unknown Gitea host; set GITEA_HOST")
+	}
+
+	var pr struct {
+		Number         int    `json:"number"`
+		Title          string `json:"title"`
+		HTMLURL        string `json:"html_url"`
+		State          string `json:"state"`
+		Merged         bool   `json:"merged"`
+		MergeCommitSHA string `json:"merge_commit_sha"`
+		Head           struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if _, err := p.get(fmt.Sprintf("repos/%s/pulls/%d", repo, number), &pr); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR details: %w", err)
+	}
+
+	feedback := &PRFeedback{
+		Repo:     repo,
+		PRNumber: pr.Number,
+		Title:    pr.Title,
+		Author:   pr.User.Login,
+		URL:      pr.HTMLURL,
+		State:    pr.State,
+	}
+	if pr.Merged {
+		feedback.State = "merged"
+		feedback.MergeCommitSHA = pr.MergeCommitSHA
+	}
+
+	if err := p.fetchReviews(repo, number, feedback); err != nil {
+		return nil, err
+	}
+	if err := p.fetchIssueComments(repo, number, feedback); err != nil {
+		return nil, err
+	}
+
+	checks, err := p.fetchStatuses(repo, pr.Head.SHA)
+	if err != nil {
+		// Don't fail the whole operation if commit statuses fail
+		warnf("failed to fetch commit statuses: %v", err)
+	} else {
+		feedback.StatusChecks = checks
+	}
+
+	return feedback, nil
+}
+
+// giteaReviewStates maps the states of submitted Gitea reviews to GitHub's,
+// which the output and JSON use. Review requests are listed as reviews too,
+// and left out.
+var giteaReviewStates = map[string]string{
+	"APPROVED":        "APPROVED",
+	"COMMENT":         "COMMENTED",
+	"REQUEST_CHANGES": "CHANGES_REQUESTED",
+}
+
+// fetchReviews adds review summaries and the unresolved line comments of
+// every submitted review.
+func (p *giteaProvider) fetchReviews(repo string, number int, feedback *PRFeedback) error {
+	type review struct {
+		ID   int    `json:"id"`
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State       string `json:"state"`
+		SubmittedAt string `json:"submitted_at"`
+		HTMLURL     string `json:"html_url"`
+		Comments    int    `json:"comments_count"`
+	}
+	var reviews []review
+	err := giteaPages(p, fmt.Sprintf("repos/%s/pulls/%d/reviews", repo, number), func(page []review) error {
+		reviews = append(reviews, page...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	for _, review := range reviews {
+		state, ok := giteaReviewStates[review.State]
+		if !ok {
+			continue
+		}
+		feedback.Reviews = append(feedback.Reviews, Review{
+			ID:          review.ID,
+			Author:      review.User.Login,
+			State:       state,
+			SubmittedAt: review.SubmittedAt,
+			HTMLURL:     review.HTMLURL,
+		})
+		if review.Body != "" {
+			feedback.GeneralIssues = append(feedback.GeneralIssues, ReviewComment{
+				ID:          review.ID,
+				Body:        review.Body,
+				Author:      review.User.Login,
+				State:       "unresolved",
+				ReviewState: state,
+				CreatedAt:   review.SubmittedAt,
+				UpdatedAt:   review.SubmittedAt,
+				HTMLURL:     review.HTMLURL,
+			})
+		}
+		if review.Comments == 0 {
+			continue
+		}
+
+		type reviewComment struct {
+			ID   int    `json:"id"`
+			Body string `json:"body"`
+			User struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			Resolver *struct {
+				Login string `json:"login"`
+			} `json:"resolver"`
+			Path             string `json:"path"`
+			DiffHunk         string `json:"diff_hunk"`
+			Position         int    `json:"position"`
+			OriginalPosition int    `json:"original_position"`
+			CreatedAt        string `json:"created_at"`
+			UpdatedAt        string `json:"updated_at"`
+		}
+		var comments []reviewComment
+		endpoint := fmt.Sprintf("repos/%s/pulls/%d/reviews/%d/comments", repo, number, review.ID)
+		err := giteaPages(p, endpoint, func(page []reviewComment) error {
+			comments = append(comments, page...)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch review comments: %w", err)
+		}
+
+		for _, c := range comments {
+			if c.Resolver != nil {
+				continue
+			}
+			comment := ReviewComment{
+				ID:            c.ID,
+				Body:          c.Body,
+				Path:          c.Path,
+				DiffHunk:      c.DiffHunk,
+				Author:        c.User.Login,
+				State:         "unresolved",
+				CreatedAt:     c.CreatedAt,
+				UpdatedAt:     c.UpdatedAt,
+				DiscussionURL: fmt.Sprintf("%s#issuecomment-%d", feedback.URL, c.ID),
+			}
+			if c.Position > 0 {
+				line := c.Position
+				comment.Line = &line
+				comment.Side = "RIGHT"
+			} else if c.OriginalPosition > 0 {
+				line := c.OriginalPosition
+				comment.Line = &line
+				comment.Side = "LEFT"
+			}
+			comment.PositionState = commentPositionState("", comment.Line, nil)
+			feedback.Comments = append(feedback.Comments, comment)
+		}
+	}
+	return nil
+}
+
+// fetchIssueComments adds the general conversation comments on the PR
+func (p *giteaProvider) fetchIssueComments(repo string, number int, feedback *PRFeedback) error {
+	type issueComment struct {
+		ID   int    `json:"id"`
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+	}
+	endpoint := fmt.Sprintf("repos/%s/issues/%d/comments", repo, number)
+	err := giteaPages(p, endpoint, func(comments []issueComment) error {
+		for _, c := range comments {
+			feedback.GeneralIssues = append(feedback.GeneralIssues, ReviewComment{
+				ID:            c.ID,
+				Body:          c.Body,
+				Author:        c.User.Login,
+				State:         "unresolved",
+				CreatedAt:     c.CreatedAt,
+				UpdatedAt:     c.UpdatedAt,
+				DiscussionURL: fmt.Sprintf("%s#issuecomment-%d", feedback.URL, c.ID),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch issue comments: %w", err)
+	}
+	return nil
+}
+
+// fetchStatuses returns the failing commit statuses on the PR's head commit
+func (p *giteaProvider) fetchStatuses(repo, sha string) ([]StatusCheck, error) {
+	if sha == "" {
+		return nil, nil
+	}
+
+	var combined struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			State     string `json:"status"`
+			TargetURL string `json:"target_url"`
+			CreatedAt string `json:"created_at"`
+			UpdatedAt string `json:"updated_at"`
+		} `json:"statuses"`
+	}
+	if _, err := p.get(fmt.Sprintf("repos/%s/commits/%s/status", repo, sha), &combined); err != nil {
+		return nil, err
+	}
+
+	var checks []StatusCheck
+	for _, status := range combined.Statuses {
+		var conclusion string
+		switch status.State {
+		case "failure":
+			conclusion = "FAILURE"
+		case "error":
+			conclusion = "ERROR"
+		}
+		if !isFailingConclusion(conclusion) {
+			continue
+		}
+		checks = append(checks, StatusCheck{
+			Name:        status.Context,
+			Status:      "COMPLETED",
+			Conclusion:  conclusion,
+			DetailsURL:  status.TargetURL,
+			StartedAt:   status.CreatedAt,
+			CompletedAt: status.UpdatedAt,
+		})
+	}
+	return checks, nil
+}
+
+func (p *giteaProvider) get(path string, out interface{}) (http.Header, error) {
+	return getJSON(p.httpClient, fmt.Sprintf("https://%s/api/v1/%s", p.host, path), p.headers, out)
+}
+
+// giteaPages calls fn with each page of a Gitea list endpoint, 50 items at a
+// time, which is the most instances allow by default
+func giteaPages[T any](p *giteaProvider, endpoint string, fn func([]T) error) error {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+	for page := 1; ; page++ {
+		var batch []T
+		if _, err := p.get(fmt.Sprintf("%s%slimit=50&page=%d", endpoint, separator, page), &batch); err != nil {
+			return err
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < 50 {
+			return nil
+		}
+	}
+}
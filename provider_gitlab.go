@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// gitlabProvider reads merge request discussions and pipeline jobs from
+// GitLab's REST API. The token comes from GITLAB_TOKEN or GL_TOKEN.
+type gitlabProvider struct {
+	host       string
+	remotePath string
+	token      string
+	httpClient *http.Client
+}
+
+func newGitLabProvider(remoteHost, remotePath string) *gitlabProvider {
+	host := os.Getenv("GITLAB_HOST")
+	if host == "" {
+		host = remoteHost
+	}
+	if host == "" {
+		host = "gitlab.com"
+	}
+
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GL_TOKEN")
+	}
+
+	return &gitlabProvider{
+		host:       host,
+		remotePath: remotePath,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *gitlabProvider) Name() string { return "gitlab" }
+
+func (p *gitlabProvider) CurrentRepo() (string, error) {
+	if p.remotePath == "" {
+		return "", fmt.Errorf("failed to determine project from the origin remote")
+	}
+	return p.remotePath, nil
+}
+
+func (p *gitlabProvider) CurrentPR(repo string) (int, error) {
+	branch, err := currentBranch()
+	if err != nil {
+		return 0, err
+	}
+
+	var mrs []struct {
+		IID int `json:"iid"`
+	}
+	endpoint := fmt.Sprintf("projects/%s/merge_requests?state=opened&source_branch=%s", url.PathEscape(repo), url.QueryEscape(branch))
+	if _, err := p.get(endpoint, &mrs); err != nil {
+		return 0, fmt.Errorf("failed to find merge request: %w", err)
+	}
+	if len(mrs) == 0 {
+		return 0, fmt.Errorf("no merge request found for current branch")
+	}
+	return mrs[0].IID, nil
+}
+
+func (p *gitlabProvider) FetchFeedback(repo string, number int) (*PRFeedback, error) {
+	project := url.PathEscape(repo)
+
+	var mr struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		WebURL         string `json:"web_url"`
+		State          string `json:"state"`
+		MergeCommitSHA string `json:"merge_commit_sha"`
+		Author         struct {
+			Username string `json:"username"`
+		} `json:"author"`
+	}
+	if _, err := p.get(fmt.Sprintf("projects/%s/merge_requests/%d", project, number), &mr); err != nil {
+		return nil, fmt.Errorf("failed to fetch merge request details: %w", err)
+	}
+
+	feedback := &PRFeedback{
+		Repo:     repo,
+		PRNumber: mr.IID,
+		Title:    mr.Title,
+		Author:   mr.Author.Username,
+		URL:      mr.WebURL,
+		State:    mr.State,
+	}
+	switch mr.State {
+	case "opened":
+		feedback.State = "open"
+	case "merged":
+		feedback.MergeCommitSHA = mr.MergeCommitSHA
+	}
+
+	if err := p.fetchDiscussions(project, number, feedback); err != nil {
+		return nil, err
+	}
+
+	checks, err := p.fetchFailedJobs(project, number)
+	if err != nil {
+		// Don't fail the whole operation if pipeline jobs fail
+		warnf("failed to fetch pipeline jobs: %v", err)
+	} else {
+		feedback.StatusChecks = checks
+	}
+
+	return feedback, nil
+}
+
+// fetchDiscussions adds every unresolved discussion on the merge request.
+// Diff discussions become line comments; the rest are general comments.
+func (p *gitlabProvider) fetchDiscussions(project string, number int, feedback *PRFeedback) error {
+	type note struct {
+		ID     int    `json:"id"`
+		Body   string `json:"body"`
+		System bool   `json:"system"`
+		Author struct {
+			Username string `json:"username"`
+		} `json:"author"`
+		CreatedAt  string `json:"created_at"`
+		UpdatedAt  string `json:"updated_at"`
+		Resolvable bool   `json:"resolvable"`
+		Resolved   bool   `json:"resolved"`
+		Position   *struct {
+			NewPath string `json:"new_path"`
+			OldPath string `json:"old_path"`
+			NewLine *int   `json:"new_line"`
+			OldLine *int   `json:"old_line"`
+		} `json:"position"`
+	}
+
+	for page := 1; ; page++ {
+		var discussions []struct {
+			ID    string `json:"id"`
+			Notes []note `json:"notes"`
+		}
+		endpoint := fmt.Sprintf("projects/%s/merge_requests/%d/discussions?per_page=100&page=%d", project, number, page)
+		headers, err := p.get(endpoint, &discussions)
+		if err != nil {
+			return fmt.Errorf("failed to fetch discussions: %w", err)
+		}
+
+		for _, discussion := range discussions {
+			if len(discussion.Notes) == 0 {
+				continue
+			}
+			root := discussion.Notes[0]
+			if root.System || (root.Resolvable && root.Resolved) {
+				continue
+			}
+
+			comment := ReviewComment{
+				ID:            root.ID,
+				Body:          root.Body,
+				Author:        root.Author.Username,
+				State:         "unresolved",
+				CreatedAt:     root.CreatedAt,
+				UpdatedAt:     root.UpdatedAt,
+				DiscussionURL: fmt.Sprintf("%s#note_%d", feedback.URL, root.ID),
+			}
+
+			if root.Position == nil {
+				feedback.GeneralIssues = append(feedback.GeneralIssues, comment)
+				continue
+			}
+
+			comment.Path = root.Position.NewPath
+			comment.Side = "RIGHT"
+			comment.Line = root.Position.NewLine
+			if comment.Line == nil && root.Position.OldLine != nil {
+				comment.Path = root.Position.OldPath
+				comment.Side = "LEFT"
+				comment.Line = root.Position.OldLine
+			}
+			comment.PositionState = commentPositionState("", comment.Line, nil)
+			feedback.Comments = append(feedback.Comments, comment)
+		}
+
+		if headers.Get("X-Next-Page") == "" {
+			break
+		}
+	}
+
+	return nil
+}
+
+// fetchFailedJobs returns the failed and canceled jobs of the merge
+// request's latest pipeline as status checks.
+func (p *gitlabProvider) fetchFailedJobs(project string, number int) ([]StatusCheck, error) {
+	var pipelines []struct {
+		ID int `json:"id"`
+	}
+	if _, err := p.get(fmt.Sprintf("projects/%s/merge_requests/%d/pipelines", project, number), &pipelines); err != nil {
+		return nil, err
+	}
+	if len(pipelines) == 0 {
+		return nil, nil
+	}
+
+	var jobs []struct {
+		ID           int    `json:"id"`
+		Name         string `json:"name"`
+		Stage        string `json:"stage"`
+		Status       string `json:"status"`
+		WebURL       string `json:"web_url"`
+		StartedAt    string `json:"started_at"`
+		FinishedAt   string `json:"finished_at"`
+		AllowFailure bool   `json:"allow_failure"`
+	}
+	endpoint := fmt.Sprintf("projects/%s/pipelines/%d/jobs?per_page=100&scope[]=failed&scope[]=canceled", project, pipelines[0].ID)
+	if _, err := p.get(endpoint, &jobs); err != nil {
+		return nil, err
+	}
+
+	var checks []StatusCheck
+	for _, job := range jobs {
+		if job.AllowFailure {
+			continue
+		}
+		conclusion := "FAILURE"
+		if job.Status == "canceled" {
+			conclusion = "CANCELLED"
+		}
+		if !isFailingConclusion(conclusion) {
+			continue
+		}
+		jobID := strconv.Itoa(job.ID)
+		checks = append(checks, StatusCheck{
+			Name:         job.Name,
+			Status:       "COMPLETED",
+			Conclusion:   conclusion,
+			DetailsURL:   job.WebURL,
+			WorkflowName: job.Stage,
+			RunID:        jobID,
+			StartedAt:    job.StartedAt,
+			CompletedAt:  job.FinishedAt,
+			CheckCommand: "glab ci trace " + jobID,
+		})
+	}
+	return checks, nil
+}
+
+func (p *gitlabProvider) get(path string, out interface{}) (http.Header, error) {
+	headers := map[string]string{}
+	if p.token != "" {
+		headers["PRIVATE-TOKEN"] = p.token
+	}
+	return getJSON(p.httpClient, fmt.Sprintf("https://%s/api/v4/%s", p.host, path), headers, out)
+}
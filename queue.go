@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"golang.org/x/term"
+)
+
+// runQueue presents the unresolved threads one at a time, asking what to do
+// with each, like working through an inbox. Threads replied to or resolved
+// are acknowledged in the PR's local state and skipped threads are
+// remembered, so an interrupted session picks up where it stopped.
+func runQueue(client *api.RESTClient, opts *options, feedback *PRFeedback) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "Error: --queue needs an interactive terminal\n")
+		os.Exit(1)
+	}
+	state, err := loadPRState(opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+		os.Exit(1)
+	}
+	if !opts.resume {
+		applyResume(feedback, state)
+	}
+
+	items := append(append([]ReviewComment{}, feedback.GeneralIssues...), feedback.Comments...)
+	if len(items) == 0 {
+		fmt.Println("No unresolved feedback left in the queue")
+		return
+	}
+
+	// Resolving needs the GraphQL thread IDs behind the REST comments
+	gql := createGraphQLClient()
+	threadIDs := make(map[int]string)
+	if threads, err := fetchReviewThreads(gql, opts.repoName, opts.prNumber); err == nil {
+		for _, thread := range threads {
+			threadIDs[thread.Comment.ID] = thread.ID
+		}
+	}
+
+	input := bufio.NewReader(os.Stdin)
+	ask := func(prompt string) (string, bool) {
+		fmt.Print(prompt)
+		line, err := input.ReadString('\n')
+		if err != nil {
+			fmt.Println()
+			return "", false
+		}
+		return strings.TrimSpace(line), true
+	}
+	save := func(comment ReviewComment, done bool) {
+		state.LastViewed = comment.ID
+		if done && !state.isAcked(comment.ID) {
+			state.Acked = append(state.Acked, comment.ID)
+		}
+		if err := savePRState(opts.prNumber, state); err != nil {
+			warnf("%v", err)
+		}
+	}
+
+	width := terminalWidth()
+	separator := strings.Repeat("─", separatorWidth())
+	for i := 0; i < len(items); i++ {
+		comment := items[i]
+		fmt.Println(separator)
+		printQueueItem(comment, i+1, len(items), width)
+
+		for {
+			answer, ok := ask(fmt.Sprintf("\n%s[r]eply, re[s]olve, s[k]ip, [e]dit, [q]uit:%s ", colorBold, colorReset))
+			if !ok {
+				return
+			}
+			switch strings.ToLower(answer) {
+			case "r", "reply":
+				body, ok := ask("Reply: ")
+				if !ok {
+					return
+				}
+				if body == "" {
+					continue
+				}
+				// General comments are answered with another general comment
+				replyTo := comment.ID
+				if i < len(feedback.GeneralIssues) {
+					replyTo = 0
+				}
+				if err := postComment(client, opts.repoName, opts.prNumber, replyTo, body); err != nil {
+					fmt.Fprintf(os.Stderr, "%s✗%s %v\n", colorRed, colorReset, err)
+					continue
+				}
+				fmt.Printf("%s✓%s Replied\n", colorGreen, colorReset)
+				save(comment, true)
+			case "s", "resolve":
+				threadID := threadIDs[comment.ID]
+				if threadID == "" {
+					fmt.Printf("%sOnly review threads can be resolved%s\n", colorGray, colorReset)
+					continue
+				}
+				if err := setThreadResolved(gql, threadID, true); err != nil {
+					fmt.Fprintf(os.Stderr, "%s✗%s %v\n", colorRed, colorReset, err)
+					continue
+				}
+				fmt.Printf("%s✓%s Resolved\n", colorGreen, colorReset)
+				save(comment, true)
+			case "k", "skip", "":
+				save(comment, false)
+			case "e", "edit":
+				if comment.Path == "" {
+					fmt.Printf("%sGeneral comments have no location to open%s\n", colorGray, colorReset)
+				} else if err := openEditorAt(comment.Path, commentLine(comment)); err != nil {
+					fmt.Fprintf(os.Stderr, "%s✗%s %v\n", colorRed, colorReset, err)
+				}
+				continue
+			case "q", "quit":
+				save(comment, false)
+				fmt.Printf("Stopped at %d of %d; run with --queue again to continue\n", i+1, len(items))
+				return
+			default:
+				continue
+			}
+			break
+		}
+	}
+	fmt.Printf("\n%s✓%s Worked through all %d thread(s)\n", colorGreen, colorReset, len(items))
+}
+
+// printQueueItem prints one thread with its position in the queue
+func printQueueItem(comment ReviewComment, n, total, width int) {
+	location := "general comment"
+	if comment.Path != "" {
+		location = comment.Path
+		if line := commentLine(comment); line > 0 {
+			location = fmt.Sprintf("%s:%d", comment.Path, line)
+		}
+	}
+	fmt.Printf("%s[%d/%d]%s %s%s%s • %s%s%s", colorGray, n, total, colorReset,
+		colorBlue, location, colorReset, colorBold+authorColor(comment.Author), comment.Author, colorReset)
+	if comment.Outdated {
+		fmt.Printf(" %s• Outdated%s", colorYellow, colorReset)
+	}
+	fmt.Print(formatThreadSize(comment))
+	fmt.Print("\n\n")
+
+	printBody(comment.Body, width)
+	printNote(comment.Note, width)
+	printPermalink(comment.HTMLURL)
+	if comment.DiffHunk != "" {
+		fmt.Println()
+		printDiffHunk(comment.DiffHunk)
+	}
+	printReplies(comment.Replies, width)
+}
+
+// openEditorAt opens $VISUAL or $EDITOR at a line of a file in the
+// repository, waiting for it to exit
+func openEditorAt(path string, line int) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	if root, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		path = filepath.Join(strings.TrimSpace(string(root)), path)
+	}
+
+	fields := strings.Fields(editor)
+	args := fields[1:]
+	switch filepath.Base(fields[0]) {
+	case "code", "code-insiders", "cursor":
+		if line > 0 {
+			path += ":" + strconv.Itoa(line)
+		}
+		args = append(args, "-g", path)
+	default:
+		if line > 0 {
+			args = append(args, "+"+strconv.Itoa(line))
+		}
+		args = append(args, path)
+	}
+	cmd := exec.Command(fields[0], args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", editor, err)
+	}
+	return nil
+}
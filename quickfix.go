@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// writeQuickfix writes a line per line comment and check annotation in the
+// path:line: message form Vim's default errorformat reads, for `vim -q`.
+// Comments on a whole file point at its first line; general comments aren't
+// on any file, so they're left out.
+func writeQuickfix(w io.Writer, feedback *PRFeedback) {
+	for _, comment := range feedback.Comments {
+		if comment.Path == "" {
+			continue
+		}
+		line := commentLine(comment)
+		if line <= 0 {
+			line = 1
+		}
+		fmt.Fprintf(w, "%s:%d: %s: %s\n", comment.Path, line, comment.Author, firstLine(comment.Body))
+	}
+	for _, check := range feedback.StatusChecks {
+		for _, annotation := range check.Annotations {
+			if annotation.Path == "" {
+				continue
+			}
+			line := annotation.StartLine
+			if line <= 0 {
+				line = 1
+			}
+			fmt.Fprintf(w, "%s:%d: %s: %s\n", annotation.Path, line, check.Name, firstLine(annotation.Message))
+		}
+	}
+}
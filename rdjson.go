@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// rdjsonResult is a Reviewdog Diagnostic Format result, as read by
+// `reviewdog -f=rdjson`
+type rdjsonResult struct {
+	Source      rdjsonSource       `json:"source"`
+	Diagnostics []rdjsonDiagnostic `json:"diagnostics"`
+}
+
+type rdjsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdjsonDiagnostic struct {
+	Message  string         `json:"message"`
+	Location rdjsonLocation `json:"location"`
+	Severity string         `json:"severity"`
+	Source   *rdjsonSource  `json:"source,omitempty"`
+	Code     *rdjsonCode    `json:"code,omitempty"`
+}
+
+type rdjsonLocation struct {
+	Path  string       `json:"path"`
+	Range *rdjsonRange `json:"range,omitempty"`
+}
+
+type rdjsonRange struct {
+	Start rdjsonPosition  `json:"start"`
+	End   *rdjsonPosition `json:"end,omitempty"`
+}
+
+type rdjsonPosition struct {
+	Line int `json:"line"`
+}
+
+type rdjsonCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// rdjsonSeverities maps comment severities and check annotation levels to
+// reviewdog severities
+var rdjsonSeverities = map[string]string{
+	severityBlocking: "ERROR",
+	severityNormal:   "WARNING",
+	severityNit:      "INFO",
+	"failure":        "ERROR",
+	"warning":        "WARNING",
+	"notice":         "INFO",
+}
+
+// writeRDJSON writes the line comments and check annotations as reviewdog
+// diagnostics, so they can feed a reviewdog pipeline. General comments
+// aren't on any file, so they're left out.
+func writeRDJSON(w io.Writer, feedback *PRFeedback) error {
+	result := rdjsonResult{
+		Source:      rdjsonSource{Name: "gh-pr-feedback", URL: feedback.URL},
+		Diagnostics: []rdjsonDiagnostic{},
+	}
+
+	for _, comment := range feedback.Comments {
+		if comment.Path == "" {
+			continue
+		}
+		severity := comment.Severity
+		if severity == "" {
+			severity = severityNormal
+		}
+		diagnostic := rdjsonDiagnostic{
+			Message:  fmt.Sprintf("@%s: %s", comment.Author, comment.Body),
+			Location: rdjsonLocation{Path: comment.Path},
+			Severity: rdjsonSeverities[severity],
+			Source:   &rdjsonSource{Name: comment.Author},
+			Code:     &rdjsonCode{Value: "review/" + severity, URL: comment.HTMLURL},
+		}
+		if line := commentLine(comment); line > 0 {
+			diagnostic.Location.Range = &rdjsonRange{Start: rdjsonPosition{Line: line}}
+			if comment.StartLine != nil && *comment.StartLine > 0 && *comment.StartLine < line {
+				diagnostic.Location.Range = &rdjsonRange{
+					Start: rdjsonPosition{Line: *comment.StartLine},
+					End:   &rdjsonPosition{Line: line},
+				}
+			}
+		}
+		result.Diagnostics = append(result.Diagnostics, diagnostic)
+	}
+
+	for _, check := range feedback.StatusChecks {
+		for _, annotation := range check.Annotations {
+			if annotation.Path == "" {
+				continue
+			}
+			severity := rdjsonSeverities[annotation.Level]
+			if severity == "" {
+				severity = "WARNING"
+			}
+			message := annotation.Message
+			if annotation.Title != "" {
+				message = annotation.Title + ": " + message
+			}
+			diagnostic := rdjsonDiagnostic{
+				Message:  message,
+				Location: rdjsonLocation{Path: annotation.Path},
+				Severity: severity,
+				Source:   &rdjsonSource{Name: check.Name, URL: check.DetailsURL},
+			}
+			if annotation.StartLine > 0 {
+				diagnostic.Location.Range = &rdjsonRange{Start: rdjsonPosition{Line: annotation.StartLine}}
+				if annotation.EndLine > annotation.StartLine {
+					diagnostic.Location.Range.End = &rdjsonPosition{Line: annotation.EndLine}
+				}
+			}
+			result.Diagnostics = append(result.Diagnostics, diagnostic)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
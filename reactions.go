@@ -0,0 +1,68 @@
+package main
+
+import "strings"
+
+// graphqlReactions maps GraphQL reaction contents to the REST names used
+// everywhere else
+var graphqlReactions = map[string]string{
+	"THUMBS_UP":   "+1",
+	"THUMBS_DOWN": "-1",
+	"LAUGH":       "laugh",
+	"HOORAY":      "hooray",
+	"CONFUSED":    "confused",
+	"HEART":       "heart",
+	"ROCKET":      "rocket",
+	"EYES":        "eyes",
+}
+
+// reactionNames lists the reactions --reaction accepts
+const reactionNames = "+1, -1, laugh, hooray, confused, heart, rocket or eyes"
+
+func validReaction(name string) bool {
+	for _, known := range graphqlReactions {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// filterReactions keeps only the threads and general comments whose first
+// comment has at least min reactions, and at least one of reaction if it's
+// set, so a team can react to what it considers blocking and the author
+// works through just that
+func filterReactions(feedback *PRFeedback, min int, reaction string) {
+	if min <= 0 && reaction == "" {
+		return
+	}
+	keep := func(comments []ReviewComment) []ReviewComment {
+		kept := comments[:0]
+		for _, comment := range comments {
+			total := 0
+			for _, count := range comment.Reactions {
+				total += count
+			}
+			if total < min || (reaction != "" && comment.Reactions[reaction] == 0) {
+				continue
+			}
+			kept = append(kept, comment)
+		}
+		return kept
+	}
+	feedback.Comments = keep(feedback.Comments)
+	feedback.GeneralIssues = keep(feedback.GeneralIssues)
+}
+
+// normalizeReaction accepts the emoji shortcodes GitHub's UI uses, e.g.
+// thumbsup, as well as the REST names
+func normalizeReaction(name string) string {
+	switch strings.ToLower(name) {
+	case "thumbsup", "thumbs_up":
+		return "+1"
+	case "thumbsdown", "thumbs_down":
+		return "-1"
+	case "tada":
+		return "hooray"
+	}
+	return strings.ToLower(name)
+}
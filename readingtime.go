@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// wordsPerMinute is a typical reading speed for technical prose
+const wordsPerMinute = 200
+
+// measureThreads records how many replies and words each thread has and
+// roughly how long it takes to read, so triage can start with the quick ones.
+// It runs before --no-replies drops the replies, which keeps the counts.
+func measureThreads(feedback *PRFeedback) {
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for i := range comments {
+			words := len(strings.Fields(comments[i].Body))
+			for _, reply := range comments[i].Replies {
+				words += len(strings.Fields(reply.Body))
+			}
+			comments[i].ReplyCount = len(comments[i].Replies)
+			comments[i].WordCount = words
+			comments[i].ReadingSeconds = (words*60 + wordsPerMinute - 1) / wordsPerMinute
+		}
+	}
+}
+
+// formatThreadSize describes a thread's length for its header, e.g.
+// " • 3 replies, ~2 min read"
+func formatThreadSize(comment ReviewComment) string {
+	var parts []string
+	switch comment.ReplyCount {
+	case 0:
+	case 1:
+		parts = append(parts, "1 reply")
+	default:
+		parts = append(parts, fmt.Sprintf("%d replies", comment.ReplyCount))
+	}
+	if comment.ReadingSeconds >= 60 {
+		parts = append(parts, fmt.Sprintf("~%d min read", (comment.ReadingSeconds+30)/60))
+	} else if comment.ReplyCount > 0 {
+		parts = append(parts, "<1 min read")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %s• %s%s", colorGray, strings.Join(parts, ", "), colorReset)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// apiRecorder saves every API response and gh invocation to a directory with
+// --record, and serves them back from it with --replay, so output can be
+// reproduced offline, e.g. for demos or reviewing changes to this tool.
+type apiRecorder struct {
+	dir    string
+	replay bool
+	// recordedAt is when the recording was made, which stands in for the
+	// current time on replay so relative times don't drift
+	recordedAt time.Time
+}
+
+// recorder is set by --record or --replay
+var recorder *apiRecorder
+
+// recording is one saved response. Bodies are kept as text so recordings
+// read well in a diff.
+type recording struct {
+	Method string              `json:"method,omitempty"`
+	URL    string              `json:"url,omitempty"`
+	Args   []string            `json:"args,omitempty"`
+	Status int                 `json:"status,omitempty"`
+	Header map[string][]string `json:"header,omitempty"`
+	Body   string              `json:"body"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// recordingMeta describes a recording as a whole
+type recordingMeta struct {
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+const recordingMetaFile = "recording.json"
+
+// startRecorder sets up --record or --replay on dir
+func startRecorder(dir string, replay bool) error {
+	rec := &apiRecorder{dir: dir, replay: replay, recordedAt: time.Now().UTC()}
+	metaPath := filepath.Join(dir, recordingMetaFile)
+	if replay {
+		data, err := os.ReadFile(metaPath)
+		if err != nil {
+			return fmt.Errorf("no recording in %s: %w", dir, err)
+		}
+		var meta recordingMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", metaPath, err)
+		}
+		rec.recordedAt = meta.RecordedAt
+	} else {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(recordingMeta{RecordedAt: rec.recordedAt}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(metaPath, append(data, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+	recorder = rec
+	return nil
+}
+
+// now is the current time, or when the recording was made on replay
+func now() time.Time {
+	if recorder != nil && recorder.replay {
+		return recorder.recordedAt
+	}
+	return time.Now()
+}
+
+// path names the file for a request after what it fetched, with a hash of
+// everything identifying it so GraphQL queries to one endpoint don't collide
+func (r *apiRecorder) path(kind, name string, key ...string) string {
+	h := sha256.New()
+	for _, part := range key {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	var b strings.Builder
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		} else if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+			b.WriteByte('-')
+		}
+	}
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > 60 {
+		slug = slug[:60]
+	}
+	return filepath.Join(r.dir, fmt.Sprintf("%s-%s-%s.json", kind, slug, hex.EncodeToString(h.Sum(nil))[:12]))
+}
+
+func (r *apiRecorder) load(path string, what string) (*recording, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("nothing recorded for %s; record it again with --record", what)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &rec, nil
+}
+
+func (r *apiRecorder) save(path string, rec *recording) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// recordTransport records or replays the API requests made through it
+type recordTransport struct {
+	base http.RoundTripper
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	url := req.URL.RequestURI()
+	path := recorder.path(strings.ToLower(req.Method), req.URL.Path, req.Method, url, string(body))
+
+	if recorder.replay {
+		rec, err := recorder.load(path, req.Method+" "+url)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+			StatusCode: rec.Status,
+			Header:     http.Header(rec.Header),
+			Body:       io.NopCloser(strings.NewReader(rec.Body)),
+			Request:    req,
+		}, nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	rec := &recording{Method: req.Method, URL: url, Status: resp.StatusCode, Header: header, Body: string(data)}
+	if err := recorder.save(path, rec); err != nil {
+		return nil, fmt.Errorf("failed to record %s: %w", url, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// ghOutput runs gh with args and returns its output, recording or replaying
+// it like API requests
+func ghOutput(args ...string) ([]byte, error) {
+	if recorder == nil {
+		return exec.Command("gh", args...).Output()
+	}
+	path := recorder.path("gh", strings.Join(args, " "), args...)
+	if recorder.replay {
+		rec, err := recorder.load(path, "gh "+strings.Join(args, " "))
+		if err != nil {
+			return nil, err
+		}
+		if rec.Error != "" {
+			return []byte(rec.Body), fmt.Errorf("%s", rec.Error)
+		}
+		return []byte(rec.Body), nil
+	}
+
+	output, err := exec.Command("gh", args...).Output()
+	rec := &recording{Args: args, Body: string(output)}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if saveErr := recorder.save(path, rec); saveErr != nil {
+		return nil, fmt.Errorf("failed to record gh %s: %w", strings.Join(args, " "), saveErr)
+	}
+	return output, err
+}
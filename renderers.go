@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CommentRenderer condenses a bot's comment into a short summary, shown in
+// place of the full body. Render returns false when it doesn't recognize the
+// comment, which is then shown as usual.
+type CommentRenderer interface {
+	Render(body string) (string, bool)
+}
+
+// commentRenderers are the available renderers by the name used in config.
+// New adapters only need registering here.
+var commentRenderers = map[string]CommentRenderer{
+	"codecov":        codecovRenderer{},
+	"terraform-plan": terraformPlanRenderer{},
+}
+
+// RendererConfig assigns a renderer to an author's comments
+type RendererConfig struct {
+	Author   string `yaml:"author"`
+	Renderer string `yaml:"renderer"`
+}
+
+// defaultRenderers apply to well-known bots unless the config assigns their
+// authors something else
+var defaultRenderers = []RendererConfig{
+	{Author: "codecov[bot]", Renderer: "codecov"},
+	{Author: "codecov-commenter", Renderer: "codecov"},
+}
+
+// applyRenderers sets the summary of every comment whose author has a
+// renderer, leaving the body intact for JSON output
+func applyRenderers(feedback *PRFeedback, configured []RendererConfig) {
+	byAuthor := make(map[string]CommentRenderer)
+	for _, assignment := range append(append([]RendererConfig{}, defaultRenderers...), configured...) {
+		if renderer, ok := commentRenderers[assignment.Renderer]; ok {
+			byAuthor[strings.ToLower(assignment.Author)] = renderer
+		}
+	}
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for i := range comments {
+			renderer, ok := byAuthor[strings.ToLower(comments[i].Author)]
+			if !ok {
+				continue
+			}
+			if summary, ok := renderer.Render(comments[i].Body); ok {
+				comments[i].Summary = summary
+			}
+		}
+	}
+}
+
+// codecovRenderer reduces a Codecov report to patch and project coverage
+type codecovRenderer struct{}
+
+var (
+	codecovPatch   = regexp.MustCompile("(?i)(?:patch|diff) coverage is `?([\\d.]+%)`?(?: with `?(\\d+) lines?`? in your changes missing coverage)?")
+	codecovProject = regexp.MustCompile("(?i)project coverage is `?([\\d.]+%)`?")
+	codecovChange  = regexp.MustCompile("(?i)will \\*\\*(increase|decrease|not change)\\*\\* coverage(?: by `?([\\d.]+%)`?)?")
+)
+
+func (codecovRenderer) Render(body string) (string, bool) {
+	var lines []string
+	if match := codecovPatch.FindStringSubmatch(body); match != nil {
+		line := "Patch coverage " + match[1]
+		if match[2] != "" {
+			line += fmt.Sprintf(" (%s line(s) missing)", match[2])
+		}
+		lines = append(lines, line)
+	}
+
+	var project []string
+	if match := codecovProject.FindStringSubmatch(body); match != nil {
+		project = append(project, "Project coverage "+match[1])
+	}
+	if match := codecovChange.FindStringSubmatch(body); match != nil {
+		change := strings.ToLower(match[1]) + "d"
+		if match[1] == "not change" {
+			change = "unchanged"
+		} else if match[2] != "" {
+			change += " by " + match[2]
+		}
+		if len(project) == 0 {
+			project = append(project, "Project coverage")
+		}
+		project = append(project, change)
+	}
+	if len(project) > 0 {
+		lines = append(lines, strings.Join(project, ", "))
+	}
+
+	if len(lines) == 0 {
+		return "", false
+	}
+	return strings.Join(lines, "\n"), true
+}
+
+// terraformPlanRenderer totals the resources added, changed and destroyed
+// across every plan in a comment, e.g. one per workspace
+type terraformPlanRenderer struct{}
+
+var terraformPlan = regexp.MustCompile(`Plan: (\d+) to add, (\d+) to change, (\d+) to destroy`)
+
+func (terraformPlanRenderer) Render(body string) (string, bool) {
+	matches := terraformPlan.FindAllStringSubmatch(body, -1)
+	if len(matches) == 0 {
+		if strings.Contains(body, "No changes.") {
+			return "Terraform plan: no changes", true
+		}
+		return "", false
+	}
+	var add, change, destroy int
+	for _, match := range matches {
+		n, _ := strconv.Atoi(match[1])
+		add += n
+		n, _ = strconv.Atoi(match[2])
+		change += n
+		n, _ = strconv.Atoi(match[3])
+		destroy += n
+	}
+	summary := fmt.Sprintf("Terraform plan: %d to add, %d to change, %d to destroy", add, change, destroy)
+	if len(matches) > 1 {
+		summary += fmt.Sprintf(" across %d plans", len(matches))
+	}
+	return summary, true
+}
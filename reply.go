@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// maxCommentLength is the longest comment body GitHub accepts
+const maxCommentLength = 65536
+
+// runReply replies to a review thread, optionally with a diff of a local fix
+// attached so the reviewer can see it before it's pushed.
+func runReply(args []string) {
+	var commentID int
+	var message, attach string
+	var dryRun bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-m" || arg == "--message" || arg == "--attach-diff":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			if arg == "--attach-diff" {
+				attach = args[i+1]
+			} else {
+				message = args[i+1]
+			}
+			i++
+		case arg == "--dry-run":
+			dryRun = true
+		case commentID == 0 && !strings.HasPrefix(arg, "-"):
+			if id, err := strconv.Atoi(arg); err == nil && id > 0 {
+				commentID = id
+				continue
+			}
+			rest = append(rest, arg)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if commentID == 0 || (message == "" && attach == "") {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback reply <comment-id> [-m message] [--attach-diff <path|range>] [--dry-run] [pr-number]\n")
+		os.Exit(1)
+	}
+
+	// Diffs are taken in the repository given on the command line, if any
+	opts := parseArgs(rest)
+	changeDir(opts)
+
+	body := message
+	if attach != "" {
+		diff, err := localDiff(attach)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		body = strings.TrimSpace(body + "\n\n" + fenceDiff(diff))
+	}
+	if len(body) > maxCommentLength {
+		fmt.Fprintf(os.Stderr, "Error: the reply is %d characters, more than the %d GitHub allows; attach a smaller diff\n", len(body), maxCommentLength)
+		os.Exit(1)
+	}
+
+	if dryRun {
+		fmt.Println(body)
+		return
+	}
+
+	client := resolvePR(opts)
+	if err := postComment(client, opts.repoName, opts.prNumber, commentID, body); err != nil {
+		fmt.Fprintf(os.Stderr, "Error posting reply: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s✓%s Replied to comment %d on PR #%d\n", colorGreen, colorReset, commentID, opts.prNumber)
+}
+
+// localDiff returns the uncommitted changes to a file or directory when
+// target is one, and otherwise the diff of target as a revision range, e.g.
+// HEAD~1 or main..fix
+func localDiff(target string) (string, error) {
+	args := []string{"diff", target}
+	if _, err := os.Stat(target); err == nil {
+		args = []string{"diff", "HEAD", "--", target}
+	}
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git diff %s: %s", target, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git diff %s: %w", target, err)
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return "", fmt.Errorf("no changes in %s to attach", target)
+	}
+	return string(output), nil
+}
+
+// fenceDiff wraps a diff in a code fence longer than any backtick run in it,
+// so diffs of Markdown files can't close it early
+func fenceDiff(diff string) string {
+	fence := "```"
+	for strings.Contains(diff, fence) {
+		fence += "`"
+	}
+	return fence + "diff\n" + strings.TrimRight(diff, "\n") + "\n" + fence
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// runResolve resolves the review threads started by the given comments, or
+// with --undo reopens them. Every numeric argument is a comment ID, so IDs
+// can be piped in with `gh pr-feedback ids | xargs gh pr-feedback resolve`;
+// the PR is the current branch's unless --pr is given.
+func runResolve(args []string) {
+	var commentIDs []int
+	var undo, dryRun bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--undo":
+			undo = true
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--pr":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --pr requires a PR number\n")
+				os.Exit(1)
+			}
+			rest = append(rest, args[i+1])
+			i++
+		case !strings.HasPrefix(arg, "-") && strings.Trim(arg, "0123456789") == "":
+			id, err := strconv.Atoi(arg)
+			if err != nil || id == 0 {
+				fmt.Fprintf(os.Stderr, "Error: invalid comment ID %q\n", arg)
+				os.Exit(1)
+			}
+			commentIDs = append(commentIDs, id)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if len(commentIDs) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback resolve <comment-id>... [--undo] [--dry-run] [--pr number]\n")
+		os.Exit(1)
+	}
+
+	opts := parseArgs(rest)
+	resolvePR(opts)
+
+	// Threads are resolved through GraphQL, by the ID of the thread behind
+	// its first comment
+	graphql := createGraphQLClient()
+	threads, err := fetchReviewThreads(graphql, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	byComment := make(map[int]reviewThread, len(threads))
+	for _, thread := range threads {
+		byComment[thread.Comment.ID] = thread
+	}
+
+	verb, done := "resolve", "Resolved"
+	if undo {
+		verb, done = "unresolve", "Unresolved"
+	}
+	failed := false
+	for _, id := range commentIDs {
+		thread, ok := byComment[id]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%s✗%s Comment %d doesn't start a review thread on PR #%d\n", colorRed, colorReset, id, opts.prNumber)
+			failed = true
+			continue
+		}
+		if thread.IsResolved != undo {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("Would %s thread %d: %s\n", verb, id, firstLine(thread.Comment.Body))
+			continue
+		}
+		if err := setThreadResolved(graphql, thread.ID, !undo); err != nil {
+			fmt.Fprintf(os.Stderr, "%s✗%s Thread %d: %v\n", colorRed, colorReset, id, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s✓%s %s thread %d: %s\n", colorGreen, colorReset, done, id, firstLine(thread.Comment.Body))
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
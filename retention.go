@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseAge parses a duration that may also use day and week units, e.g.
+// "90d", "2w" or "36h".
+func parseAge(value string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// applyRetention blanks the text of comments created more than window ago,
+// keeping their metadata, and returns how many were redacted. Everything
+// derived from a comment's text goes too: its edit history, a renderer's
+// summary of it and the analyzers' messages about it.
+func applyRetention(feedback *PRFeedback, window time.Duration) int {
+	cutoff := now().Add(-window)
+	redacted := 0
+	redact := func(comment *ReviewComment) {
+		created, err := parseTime(comment.CreatedAt)
+		if err != nil || !created.Before(cutoff) {
+			return
+		}
+		comment.Body = ""
+		comment.PreviousBody = ""
+		comment.Summary = ""
+		for i := range comment.Annotations {
+			comment.Annotations[i].Message = ""
+		}
+		comment.Redacted = true
+		redacted++
+	}
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for i := range comments {
+			redact(&comments[i])
+			for j := range comments[i].Replies {
+				redact(&comments[i].Replies[j])
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// reviewEvents maps the verdict flags of `review` to GitHub's review events
+var reviewEvents = map[string]string{
+	"--approve":         "APPROVE",
+	"--request-changes": "REQUEST_CHANGES",
+	"--comment":         "COMMENT",
+}
+
+// runReview submits your own review of the PR. A pending review you've been
+// drafting is submitted with it, so its comments go out rather than being
+// left behind.
+func runReview(args []string) {
+	var event, message string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case reviewEvents[arg] != "":
+			if event != "" && event != reviewEvents[arg] {
+				fmt.Fprintf(os.Stderr, "Error: only one of --approve, --request-changes and --comment can be given\n")
+				os.Exit(1)
+			}
+			event = reviewEvents[arg]
+		case arg == "--message" || arg == "-m":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			message = args[i+1]
+			i++
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if event == "" {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback review --approve|--request-changes|--comment [-m message] [pr-number]\n")
+		os.Exit(1)
+	}
+	// GitHub rejects change requests and comment reviews without a body
+	if message == "" && event != "APPROVE" {
+		fmt.Fprintf(os.Stderr, "Error: -m is required with --request-changes and --comment\n")
+		os.Exit(1)
+	}
+
+	opts := parseArgs(rest)
+	client := resolvePR(opts)
+
+	payload, err := json.Marshal(map[string]string{"event": event, "body": message})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Only your own pending review is listed, and GitHub allows one at a time
+	reviewsEndpoint := fmt.Sprintf("repos/%s/pulls/%d/reviews", opts.repoName, opts.prNumber)
+	reviews, err := getAllPages[Review](client, reviewsEndpoint)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching reviews: %v\n", err)
+		os.Exit(1)
+	}
+	pendingID := 0
+	for _, review := range reviews {
+		if review.State == "PENDING" {
+			pendingID = review.ID
+		}
+	}
+
+	endpoint := reviewsEndpoint
+	if pendingID != 0 {
+		endpoint = fmt.Sprintf("%s/%d/events", reviewsEndpoint, pendingID)
+	}
+	var response struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := client.Post(endpoint, bytes.NewReader(payload), &response); err != nil {
+		fmt.Fprintf(os.Stderr, "Error submitting review: %v\n", err)
+		os.Exit(1)
+	}
+
+	verdict := "Commented on"
+	switch event {
+	case "APPROVE":
+		verdict = "Approved"
+	case "REQUEST_CHANGES":
+		verdict = "Requested changes on"
+	}
+	fmt.Printf("%s✓%s %s PR #%d", colorGreen, colorReset, verdict, opts.prNumber)
+	if pendingID != 0 {
+		fmt.Printf(", submitting your pending review")
+	}
+	fmt.Println()
+	printPermalink(response.HTMLURL)
+}
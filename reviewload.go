@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// ReviewRequest is a pending request for a user or team to review a PR
+type ReviewRequest struct {
+	Reviewer    string `json:"reviewer"`
+	IsTeam      bool   `json:"is_team,omitempty"`
+	RequestedAt string `json:"requested_at,omitempty"`
+}
+
+// ReviewerLoad summarizes the open review requests waiting on one reviewer
+type ReviewerLoad struct {
+	Reviewer     string   `json:"reviewer"`
+	IsTeam       bool     `json:"is_team,omitempty"`
+	OpenRequests int      `json:"open_requests"`
+	P50Hours     float64  `json:"p50_hours"`
+	P90Hours     float64  `json:"p90_hours"`
+	OldestHours  float64  `json:"oldest_hours"`
+	PRs          []string `json:"prs"`
+}
+
+// fetchReviewRequests returns the reviewers currently requested on a PR and
+// when each was most recently asked.
+func fetchReviewRequests(client *api.RESTClient, repo string, prNumber int) ([]ReviewRequest, error) {
+	var requested struct {
+		Users []struct {
+			Login string `json:"login"`
+		} `json:"users"`
+		Teams []struct {
+			Slug string `json:"slug"`
+		} `json:"teams"`
+	}
+	endpoint := fmt.Sprintf("repos/%s/pulls/%d/requested_reviewers", repo, prNumber)
+	if err := client.Get(endpoint, &requested); err != nil {
+		return nil, fmt.Errorf("failed to fetch requested reviewers: %w", err)
+	}
+	if len(requested.Users) == 0 && len(requested.Teams) == 0 {
+		return nil, nil
+	}
+
+	// The issue events record when each review was requested
+	type issueEvent struct {
+		Event             string `json:"event"`
+		CreatedAt         string `json:"created_at"`
+		RequestedReviewer *struct {
+			Login string `json:"login"`
+		} `json:"requested_reviewer"`
+		RequestedTeam *struct {
+			Slug string `json:"slug"`
+		} `json:"requested_team"`
+	}
+	requestedAt := make(map[string]string)
+	endpoint = fmt.Sprintf("repos/%s/issues/%d/events", repo, prNumber)
+	err := eachPage(client, endpoint, func(events []issueEvent) error {
+		for _, event := range events {
+			if event.Event != "review_requested" {
+				continue
+			}
+			if event.RequestedReviewer != nil {
+				requestedAt[event.RequestedReviewer.Login] = event.CreatedAt
+			}
+			if event.RequestedTeam != nil {
+				requestedAt["team:"+event.RequestedTeam.Slug] = event.CreatedAt
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PR events: %w", err)
+	}
+
+	var requests []ReviewRequest
+	for _, user := range requested.Users {
+		requests = append(requests, ReviewRequest{Reviewer: user.Login, RequestedAt: requestedAt[user.Login]})
+	}
+	for _, team := range requested.Teams {
+		requests = append(requests, ReviewRequest{Reviewer: team.Slug, IsTeam: true, RequestedAt: requestedAt["team:"+team.Slug]})
+	}
+	return requests, nil
+}
+
+// runReviewLoad reports open review requests per reviewer across an
+// organization so leads can rebalance assignments.
+func runReviewLoad(opts *options) {
+	client := newClient(opts)
+
+	refs, err := searchPRs(client, "is:pr is:open draft:false org:"+opts.org)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing pull requests: %v\n", err)
+		os.Exit(1)
+	}
+
+	type waiting struct {
+		isTeam bool
+		ages   []time.Duration
+		prs    []string
+	}
+	byReviewer := make(map[string]*waiting)
+	var mu sync.Mutex
+	asOf := now()
+
+	forEachPR(refs, func(i int, ref prRef) {
+		requests, err := fetchReviewRequests(client, ref.Repo, ref.Number)
+		if err != nil {
+			warnf("failed to fetch %s#%d: %v", ref.Repo, ref.Number, err)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, request := range requests {
+			name := request.Reviewer
+			if request.IsTeam {
+				name = opts.org + "/" + request.Reviewer
+			}
+			w, ok := byReviewer[name]
+			if !ok {
+				w = &waiting{isTeam: request.IsTeam}
+				byReviewer[name] = w
+			}
+			var age time.Duration
+			if t, err := parseTime(request.RequestedAt); err == nil {
+				age = asOf.Sub(t)
+			}
+			w.ages = append(w.ages, age)
+			w.prs = append(w.prs, fmt.Sprintf("%s#%d", ref.Repo, ref.Number))
+		}
+	})
+
+	var loads []ReviewerLoad
+	for name, w := range byReviewer {
+		sort.Slice(w.ages, func(i, j int) bool { return w.ages[i] < w.ages[j] })
+		sort.Strings(w.prs)
+		loads = append(loads, ReviewerLoad{
+			Reviewer:     name,
+			IsTeam:       w.isTeam,
+			OpenRequests: len(w.ages),
+			P50Hours:     percentile(w.ages, 50).Hours(),
+			P90Hours:     percentile(w.ages, 90).Hours(),
+			OldestHours:  w.ages[len(w.ages)-1].Hours(),
+			PRs:          w.prs,
+		})
+	}
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].OpenRequests != loads[j].OpenRequests {
+			return loads[i].OpenRequests > loads[j].OpenRequests
+		}
+		return loads[i].Reviewer < loads[j].Reviewer
+	})
+
+	if opts.jsonOutput {
+		printJSON(opts, loads)
+		return
+	}
+
+	fmt.Printf("%sReview load for %s%s %s(%d open PRs)%s\n\n", colorBold, opts.org, colorReset, colorGray, len(refs), colorReset)
+	if len(loads) == 0 {
+		fmt.Println("No pending review requests")
+		return
+	}
+	fmt.Printf("%s%-28s %6s %8s %8s %8s%s\n", colorGray, "REVIEWER", "OPEN", "P50", "P90", "OLDEST", colorReset)
+	for _, load := range loads {
+		name := load.Reviewer
+		if load.IsTeam {
+			name = "@" + name
+		}
+		fmt.Printf("%-28s %6d %8s %8s %8s\n", name, load.OpenRequests,
+			formatAge(hoursToDuration(load.P50Hours)),
+			formatAge(hoursToDuration(load.P90Hours)),
+			formatAge(hoursToDuration(load.OldestHours)))
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func hoursToDuration(hours float64) time.Duration {
+	return time.Duration(hours * float64(time.Hour))
+}
+
+// formatAge renders a waiting time compactly, e.g. "45m", "7h" or "3d"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+)
+
+// Review is a review submitted on the PR, whatever its state
+type Review struct {
+	ID          int    `json:"id"`
+	Author      string `json:"author"`
+	State       string `json:"state"`
+	SubmittedAt string `json:"submitted_at"`
+	HTMLURL     string `json:"html_url,omitempty"`
+}
+
+const reviewDecisionQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) { reviewDecision }
+  }
+}`
+
+// fetchReviewDecision returns the PR's overall review decision, e.g.
+// CHANGES_REQUESTED or REVIEW_REQUIRED, which only GraphQL reports. It's
+// empty when the repository doesn't require reviews.
+func fetchReviewDecision(repo string, prNumber int) string {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return ""
+	}
+	var response struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewDecision string `json:"reviewDecision"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	variables := map[string]interface{}{"owner": owner, "name": name, "number": prNumber}
+	if err := createGraphQLClient().Do(reviewDecisionQuery, variables, &response); err != nil {
+		// Tokens that can't use GraphQL were already reported by the thread lookup
+		if !budgetSkipped(err) && !isScopeError(err) {
+			warnf("failed to fetch review decision: %v", err)
+		}
+		return ""
+	}
+	return response.Repository.PullRequest.ReviewDecision
+}
+
+func formatReviewDecision(decision string) string {
+	switch decision {
+	case "APPROVED":
+		return colorGreen + "Approved" + colorReset
+	case "CHANGES_REQUESTED":
+		return colorRed + "Changes requested" + colorReset
+	case "REVIEW_REQUIRED":
+		return colorYellow + "Review required" + colorReset
+	}
+	return ""
+}
+
+// formatReviewStates lists each reviewer's latest approval or change
+// request, e.g. "alice approved, bob requested changes". Comment-only
+// reviews don't change where a reviewer stands, so they're skipped.
+func formatReviewStates(reviews []Review) string {
+	var order []string
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			if _, ok := latest[review.Author]; !ok {
+				order = append(order, review.Author)
+			}
+			latest[review.Author] = review.State
+		}
+	}
+
+	var parts []string
+	for _, author := range order {
+		switch latest[author] {
+		case "APPROVED":
+			parts = append(parts, authorColor(author)+author+colorReset+" approved")
+		case "CHANGES_REQUESTED":
+			parts = append(parts, authorColor(author)+author+colorReset+" requested changes")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reviewVerb describes what a review did, for its header
+func reviewVerb(state string) string {
+	switch state {
+	case "APPROVED":
+		return "approved"
+	case "CHANGES_REQUESTED":
+		return "requested changes"
+	case "DISMISSED":
+		return "reviewed (dismissed)"
+	}
+	return "commented"
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// runRevisit reopens resolved review threads matching a filter, e.g. after a
+// bot resolved a batch of threads it shouldn't have.
+func runRevisit(args []string) {
+	match := &CommentMatch{}
+	var resolvedBy []string
+	var dryRun bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--author", "--path", "--match", "--resolved-by":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			i++
+			switch arg {
+			case "--author":
+				match.Authors = append(match.Authors, args[i])
+			case "--path":
+				match.Paths = append(match.Paths, args[i])
+			case "--match":
+				match.Match = args[i]
+			case "--resolved-by":
+				resolvedBy = append(resolvedBy, args[i])
+			}
+		case "--dry-run":
+			dryRun = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if len(match.Authors) == 0 && len(match.Paths) == 0 && match.Match == "" && len(resolvedBy) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback revisit [--author login] [--path glob] [--match regex] [--resolved-by login] [--dry-run] [pr-number]\n")
+		fmt.Fprintf(os.Stderr, "At least one filter is required\n")
+		os.Exit(1)
+	}
+
+	var pattern *regexp.Regexp
+	if match.Match != "" {
+		var err error
+		pattern, err = regexp.Compile(match.Match)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --match pattern: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	opts := parseArgs(rest)
+	resolvePR(opts)
+	client := createGraphQLClient()
+
+	threads, err := fetchReviewThreads(client, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	reopened := 0
+	for _, thread := range threads {
+		if !thread.IsResolved || !commentMatches(match, pattern, thread.Comment) {
+			continue
+		}
+		if len(resolvedBy) > 0 && !containsFold(resolvedBy, thread.ResolvedBy) {
+			continue
+		}
+
+		if !dryRun {
+			if err := setThreadResolved(client, thread.ID, false); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		reopened++
+
+		location := thread.Comment.Path
+		if thread.Comment.Line != nil {
+			location = fmt.Sprintf("%s:%d", location, *thread.Comment.Line)
+		}
+		fmt.Printf("%s↺%s %s %s(%s, resolved by %s)%s\n", colorYellow, colorReset, location, colorGray, thread.Comment.Author, thread.ResolvedBy, colorReset)
+		fmt.Printf("  %s\n", firstLine(strings.TrimSpace(thread.Comment.Body)))
+	}
+
+	verb := "Reopened"
+	if dryRun {
+		verb = "Would reopen"
+	}
+	fmt.Printf("\n%s %d thread(s) on PR #%d\n", verb, reopened, opts.prNumber)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// sarifSchema is the SARIF version --format sarif writes
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	} `json:"driver"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	// Properties carry what SARIF has no field for, e.g. the reviewer
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// sarifLevels maps comment severities and check annotation levels to SARIF
+// result levels
+var sarifLevels = map[string]string{
+	severityBlocking: "error",
+	severityNormal:   "warning",
+	severityNit:      "note",
+	"failure":        "error",
+	"warning":        "warning",
+	"notice":         "note",
+}
+
+// writeSARIF writes the line comments and check annotations as a SARIF log,
+// so IDEs and code scanning dashboards can show them in place. General
+// comments aren't on any file, so they're left out.
+func writeSARIF(w io.Writer, feedback *PRFeedback) error {
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver.Name = "gh-pr-feedback"
+	run.Tool.Driver.InformationURI = "https://github.com/lox/gh-pr-feedback"
+	rules := make(map[string]string)
+
+	for _, comment := range feedback.Comments {
+		if comment.Path == "" {
+			continue
+		}
+		severity := comment.Severity
+		if severity == "" {
+			severity = severityNormal
+		}
+		ruleID := "review/" + severity
+		rules[ruleID] = fmt.Sprintf("Review comment (%s)", severity)
+
+		var region *sarifRegion
+		if line := commentLine(comment); line > 0 {
+			region = &sarifRegion{StartLine: line}
+			if comment.StartLine != nil && *comment.StartLine > 0 && *comment.StartLine < line {
+				region = &sarifRegion{StartLine: *comment.StartLine, EndLine: line}
+			}
+		}
+		result := sarifResult{
+			RuleID:    ruleID,
+			Level:     sarifLevels[severity],
+			Message:   sarifMessage{Text: fmt.Sprintf("@%s: %s", comment.Author, comment.Body)},
+			Locations: []sarifLocation{sarifFileLocation(comment.Path, region)},
+			Properties: map[string]any{
+				"author":   comment.Author,
+				"outdated": comment.Outdated,
+			},
+		}
+		if comment.HTMLURL != "" {
+			result.Properties["url"] = comment.HTMLURL
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	for _, check := range feedback.StatusChecks {
+		for _, annotation := range check.Annotations {
+			if annotation.Path == "" {
+				continue
+			}
+			ruleID := "check/" + check.Name
+			rules[ruleID] = fmt.Sprintf("Failing check %s", check.Name)
+
+			var region *sarifRegion
+			if annotation.StartLine > 0 {
+				region = &sarifRegion{StartLine: annotation.StartLine}
+				if annotation.EndLine > annotation.StartLine {
+					region.EndLine = annotation.EndLine
+				}
+			}
+			level := sarifLevels[annotation.Level]
+			if level == "" {
+				level = "warning"
+			}
+			message := annotation.Message
+			if annotation.Title != "" {
+				message = annotation.Title + ": " + message
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:     ruleID,
+				Level:      level,
+				Message:    sarifMessage{Text: message},
+				Locations:  []sarifLocation{sarifFileLocation(annotation.Path, region)},
+				Properties: map[string]any{"details_url": check.DetailsURL},
+			})
+		}
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	run.Tool.Driver.Rules = []sarifRule{}
+	for _, id := range ids {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: rules[id]}})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}})
+}
+
+func sarifFileLocation(path string, region *sarifRegion) sarifLocation {
+	var location sarifLocation
+	location.PhysicalLocation.ArtifactLocation.URI = path
+	location.PhysicalLocation.Region = region
+	return location
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// resolveScope defaults --scope to the directory within the repository the
+// command is run from, so in a monorepo running from a service's directory
+// shows only the feedback on that service. There's no default when --repo
+// names a repository other than the checkout's.
+func resolveScope(opts *options) {
+	if opts.scopeSet || opts.repoName != "" {
+		return
+	}
+	output, err := exec.Command("git", "-C", opts.targetDir, "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return
+	}
+	opts.scope = cleanScope(strings.TrimSpace(string(output)))
+	if opts.scope != "" {
+		fmt.Fprintf(os.Stderr, "Scoped to %s; use --scope / for the whole repository\n", opts.scope)
+	}
+}
+
+// cleanScope turns a --scope directory into a path relative to the
+// repository root, or "" for the whole repository
+func cleanScope(dir string) string {
+	dir = path.Clean("/" + strings.ReplaceAll(dir, "\\", "/"))
+	return strings.TrimPrefix(dir, "/")
+}
+
+// inScope reports whether a file is within the --scope directory
+func inScope(scope, file string) bool {
+	return scope == "" || file == scope || strings.HasPrefix(file, scope+"/")
+}
+
+// filterScope keeps only the line comments and check annotations on files
+// within scope. General comments and the checks themselves aren't about any
+// one file, so they're kept.
+func filterScope(feedback *PRFeedback, scope string) {
+	if scope == "" {
+		return
+	}
+	kept := feedback.Comments[:0]
+	for _, comment := range feedback.Comments {
+		if inScope(scope, comment.Path) {
+			kept = append(kept, comment)
+		}
+	}
+	feedback.Comments = kept
+	for i, check := range feedback.StatusChecks {
+		var annotations []CheckAnnotation
+		for _, annotation := range check.Annotations {
+			if inScope(scope, annotation.Path) {
+				annotations = append(annotations, annotation)
+			}
+		}
+		feedback.StatusChecks[i].Annotations = annotations
+	}
+}
+
+// scopeFiles keeps only the changed files within scope
+func scopeFiles(files []PRFile, scope string) []PRFile {
+	if scope == "" {
+		return files
+	}
+	var kept []PRFile
+	for _, file := range files {
+		if inScope(scope, file.Filename) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Comment severities, from most to least urgent
+const (
+	severityBlocking = "blocking"
+	severityNormal   = "normal"
+	severityNit      = "nit"
+)
+
+// ScoreWeights are the points each kind of feedback adds to a PR's score.
+// They can be overridden under `score:` in the repository config.
+type ScoreWeights struct {
+	Blocking      int `yaml:"blocking"`
+	Normal        int `yaml:"normal"`
+	Nit           int `yaml:"nit"`
+	RequiredCheck int `yaml:"required_check"`
+}
+
+var defaultScoreWeights = ScoreWeights{
+	Blocking:      3,
+	Normal:        1,
+	Nit:           0,
+	RequiredCheck: 5,
+}
+
+// FeedbackScore is a single number ranking how much attention a PR needs,
+// with the counts it was computed from.
+type FeedbackScore struct {
+	Total                 int `json:"total"`
+	Blocking              int `json:"blocking"`
+	Normal                int `json:"normal"`
+	Nits                  int `json:"nits"`
+	FailingRequiredChecks int `json:"failing_required_checks"`
+}
+
+// conventionalLabel matches the "label (decorations):" prefix of
+// https://conventionalcomments.org, optionally in bold
+var conventionalLabel = regexp.MustCompile(`^\**\s*([a-z-]+)\s*(?:\(([^)]*)\))?\s*\**\s*:`)
+
+// blockerWord matches "blocker" as a word, along with a negation before it
+// such as "not a blocker" or "non-blocker"
+var blockerWord = regexp.MustCompile(`(\bnon-|\bno |\bnot |\bnot an? |\bisn't an? |\bisn’t an? )?\bblockers?\b`)
+
+// mentionsBlocker reports whether text calls something a blocker, and not
+// just that it isn't one
+func mentionsBlocker(text string) bool {
+	for _, match := range blockerWord.FindAllStringSubmatch(text, -1) {
+		if match[1] == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// commentSeverity classifies a comment body as blocking, normal or a nit
+func commentSeverity(body string) string {
+	text := strings.ToLower(strings.TrimSpace(body))
+
+	if match := conventionalLabel.FindStringSubmatch(text); match != nil {
+		label, decorations := match[1], match[2]
+		switch {
+		case strings.Contains(decorations, "non-blocking"), strings.Contains(decorations, "if-minor"):
+			return severityNit
+		case strings.Contains(decorations, "blocking"):
+			return severityBlocking
+		case label == "nit" || label == "nitpick":
+			return severityNit
+		case label == "blocker" || label == "blocking":
+			return severityBlocking
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(text, "nit"):
+		return severityNit
+	case strings.Contains(text, "[blocking]"), mentionsBlocker(text), strings.Contains(text, "must fix"):
+		return severityBlocking
+	}
+	return severityNormal
+}
+
+// scoreFeedback sets the severity of every comment and computes the PR's
+// weighted score.
+func scoreFeedback(feedback *PRFeedback, weights ScoreWeights) *FeedbackScore {
+	score := &FeedbackScore{}
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		for i := range comments {
+			// Resolved, minimized or addressed feedback and approvals
+			// aren't waiting on anything
+			if comments[i].Generated || comments[i].State == "resolved" || comments[i].Minimized || comments[i].AddressedIn != "" || comments[i].ReviewState == "APPROVED" {
+				continue
+			}
+			comments[i].Severity = commentSeverity(comments[i].Body)
+			switch comments[i].Severity {
+			case severityBlocking:
+				score.Blocking++
+			case severityNit:
+				score.Nits++
+			default:
+				score.Normal++
+			}
+		}
+	}
+	for _, check := range feedback.StatusChecks {
+		if check.Required {
+			score.FailingRequiredChecks++
+		}
+	}
+
+	score.Total = score.Blocking*weights.Blocking +
+		score.Normal*weights.Normal +
+		score.Nits*weights.Nit +
+		score.FailingRequiredChecks*weights.RequiredCheck
+	return score
+}
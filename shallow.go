@@ -0,0 +1,374 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// shallowItems is how many of the newest items per section --shallow shows
+const shallowItems = 5
+
+// FeedbackCounts are the full totals behind a shallow fetch, whose lists only
+// hold the newest items.
+type FeedbackCounts struct {
+	UnresolvedThreads int `json:"unresolved_threads"`
+	GeneralComments   int `json:"general_comments"`
+	FailingChecks     int `json:"failing_checks"`
+}
+
+const shallowQuery = `query($owner: String!, $name: String!, $number: Int!, $items: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      number
+      title
+      author { login }
+      url
+      state
+      reviewDecision
+      mergeCommit { oid }
+      reviewThreads(last: 100) {
+        pageInfo { hasPreviousPage startCursor }
+        nodes { ...shallowThread }
+      }
+      comments(last: $items) {
+        totalCount
+        nodes { databaseId url body isMinimized minimizedReason author { __typename login } authorAssociation createdAt updatedAt reactionGroups { content reactors { totalCount } } }
+      }
+      commits(last: 1) {
+        nodes {
+          commit {
+            statusCheckRollup {
+              contexts(last: 100) {
+                nodes {
+                  __typename
+                  ... on CheckRun {
+                    name
+                    status
+                    conclusion
+                    detailsUrl
+                    startedAt
+                    completedAt
+                    isRequired(pullRequestNumber: $number)
+                    checkSuite { workflowRun { workflow { name } } }
+                  }
+                  ... on StatusContext {
+                    context
+                    state
+                    targetUrl
+                    createdAt
+                    isRequired(pullRequestNumber: $number)
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}` + shallowThreadFragment
+
+// shallowThreadsQuery pages back through the threads before the newest 100,
+// which GitHub can't filter by whether they're resolved
+const shallowThreadsQuery = `query($owner: String!, $name: String!, $number: Int!, $cursor: String!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(last: 100, before: $cursor) {
+        pageInfo { hasPreviousPage startCursor }
+        nodes { ...shallowThread }
+      }
+    }
+  }
+}` + shallowThreadFragment
+
+const shallowThreadFragment = `
+fragment shallowThread on PullRequestReviewThread {
+  isResolved
+  isOutdated
+  path
+  line
+  originalLine
+  comments(first: 1) {
+    nodes { databaseId url body isMinimized minimizedReason author { __typename login } authorAssociation createdAt updatedAt originalCommit { oid } reactionGroups { content reactors { totalCount } } }
+  }
+}`
+
+type shallowThread struct {
+	IsResolved   bool   `json:"isResolved"`
+	IsOutdated   bool   `json:"isOutdated"`
+	Path         string `json:"path"`
+	Line         *int   `json:"line"`
+	OriginalLine *int   `json:"originalLine"`
+	Comments     struct {
+		Nodes []shallowComment `json:"nodes"`
+	} `json:"comments"`
+}
+
+// shallowThreadPage is a page of threads, oldest first
+type shallowThreadPage struct {
+	PageInfo struct {
+		HasPreviousPage bool   `json:"hasPreviousPage"`
+		StartCursor     string `json:"startCursor"`
+	} `json:"pageInfo"`
+	Nodes []shallowThread `json:"nodes"`
+}
+
+type shallowComment struct {
+	DatabaseID      int    `json:"databaseId"`
+	Body            string `json:"body"`
+	URL             string `json:"url"`
+	IsMinimized     bool   `json:"isMinimized"`
+	MinimizedReason string `json:"minimizedReason"`
+	Author          *struct {
+		Typename string `json:"__typename"`
+		Login    string `json:"login"`
+	} `json:"author"`
+	AuthorAssociation string `json:"authorAssociation"`
+	CreatedAt         string `json:"createdAt"`
+	UpdatedAt         string `json:"updatedAt"`
+	OriginalCommit    *struct {
+		OID string `json:"oid"`
+	} `json:"originalCommit"`
+	ReactionGroups []struct {
+		Content  string `json:"content"`
+		Reactors struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"reactors"`
+	} `json:"reactionGroups"`
+}
+
+func (c shallowComment) reviewComment() ReviewComment {
+	comment := ReviewComment{
+		ID:          c.DatabaseID,
+		Body:        c.Body,
+		AuthorAssoc: c.AuthorAssociation,
+		State:       "unresolved",
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+		HTMLURL:     c.URL,
+		Minimized:   c.IsMinimized,
+	}
+	if c.IsMinimized {
+		comment.MinimizedReason = c.MinimizedReason
+	}
+	if c.Author != nil {
+		comment.Author = c.Author.Login
+		comment.AuthorIsBot = c.Author.Typename == "Bot"
+	}
+	if c.OriginalCommit != nil {
+		comment.CommitID = c.OriginalCommit.OID
+	}
+	for _, group := range c.ReactionGroups {
+		name, ok := graphqlReactions[group.Content]
+		if !ok || group.Reactors.TotalCount == 0 {
+			continue
+		}
+		if comment.Reactions == nil {
+			comment.Reactions = make(map[string]int)
+		}
+		comment.Reactions[name] = group.Reactors.TotalCount
+	}
+	comment.Endorsements = comment.Reactions["+1"]
+	return comment
+}
+
+// fetchShallowFeedback fetches the PR's totals and only its newest items in a
+// single GraphQL query, for quick glances where a full fetch is too slow. PRs
+// with more than 100 review threads take another query per 100 to count them.
+// The totals are returned in the feedback's Counts.
+func fetchShallowFeedback(client *api.GraphQLClient, opts *options, repo string, prNumber int) (*PRFeedback, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repository %q", repo)
+	}
+
+	var response struct {
+		Repository struct {
+			PullRequest struct {
+				Number int    `json:"number"`
+				Title  string `json:"title"`
+				Author *struct {
+					Login string `json:"login"`
+				} `json:"author"`
+				URL            string `json:"url"`
+				State          string `json:"state"`
+				ReviewDecision string `json:"reviewDecision"`
+				MergeCommit    *struct {
+					Oid string `json:"oid"`
+				} `json:"mergeCommit"`
+				ReviewThreads shallowThreadPage `json:"reviewThreads"`
+				Comments      struct {
+					TotalCount int              `json:"totalCount"`
+					Nodes      []shallowComment `json:"nodes"`
+				} `json:"comments"`
+				Commits struct {
+					Nodes []struct {
+						Commit struct {
+							StatusCheckRollup *struct {
+								Contexts struct {
+									Nodes []struct {
+										Typename    string `json:"__typename"`
+										Name        string `json:"name"`
+										Status      string `json:"status"`
+										Conclusion  string `json:"conclusion"`
+										DetailsURL  string `json:"detailsUrl"`
+										StartedAt   string `json:"startedAt"`
+										CompletedAt string `json:"completedAt"`
+										IsRequired  bool   `json:"isRequired"`
+										CheckSuite  *struct {
+											WorkflowRun *struct {
+												Workflow struct {
+													Name string `json:"name"`
+												} `json:"workflow"`
+											} `json:"workflowRun"`
+										} `json:"checkSuite"`
+										Context   string `json:"context"`
+										State     string `json:"state"`
+										TargetURL string `json:"targetUrl"`
+										CreatedAt string `json:"createdAt"`
+									} `json:"nodes"`
+								} `json:"contexts"`
+							} `json:"statusCheckRollup"`
+						} `json:"commit"`
+					} `json:"nodes"`
+				} `json:"commits"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"name":   name,
+		"number": prNumber,
+		"items":  shallowItems,
+	}
+	if err := client.Do(shallowQuery, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR: %w", err)
+	}
+
+	pr := response.Repository.PullRequest
+	feedback := &PRFeedback{
+		Repo:           repo,
+		PRNumber:       pr.Number,
+		Title:          pr.Title,
+		URL:            pr.URL,
+		State:          strings.ToLower(pr.State),
+		ReviewDecision: pr.ReviewDecision,
+	}
+	if pr.MergeCommit != nil {
+		feedback.MergeCommitSHA = pr.MergeCommit.Oid
+	}
+	if pr.Author != nil {
+		feedback.Author = pr.Author.Login
+	}
+	counts := &FeedbackCounts{GeneralComments: pr.Comments.TotalCount}
+	feedback.Counts = counts
+
+	// Walk back from the newest thread, counting every unresolved one and
+	// keeping the newest that would be shown, so resolved threads can't
+	// crowd the unresolved ones out
+	var newest []shallowThread
+	page := pr.ReviewThreads
+	for {
+		for i := len(page.Nodes) - 1; i >= 0; i-- {
+			thread := page.Nodes[i]
+			if !thread.IsResolved {
+				counts.UnresolvedThreads++
+			}
+			if len(newest) < shallowItems && shallowThreadShown(thread, opts) {
+				newest = append(newest, thread)
+			}
+		}
+		if !page.PageInfo.HasPreviousPage {
+			break
+		}
+		var earlier struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads shallowThreadPage `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		pageVariables := map[string]interface{}{
+			"owner":  owner,
+			"name":   name,
+			"number": prNumber,
+			"cursor": page.PageInfo.StartCursor,
+		}
+		if err := client.Do(shallowThreadsQuery, pageVariables, &earlier); err != nil {
+			return nil, fmt.Errorf("failed to fetch review threads: %w", err)
+		}
+		page = earlier.Repository.PullRequest.ReviewThreads
+	}
+	for i := len(newest) - 1; i >= 0; i-- {
+		thread := newest[i]
+		comment := thread.Comments.Nodes[0].reviewComment()
+		if thread.IsResolved {
+			comment.State = "resolved"
+		}
+		comment.Path = thread.Path
+		comment.Line = thread.Line
+		comment.OriginalLine = thread.OriginalLine
+		comment.Outdated = thread.IsOutdated
+		comment.PositionState = commentPositionState("", thread.Line, thread.OriginalLine)
+		comment.DiscussionURL = discussionURL(feedback.URL, comment)
+		feedback.Comments = append(feedback.Comments, comment)
+	}
+	for _, c := range pr.Comments.Nodes {
+		comment := c.reviewComment()
+		if comment.Minimized && !opts.showMinimized {
+			continue
+		}
+		comment.DiscussionURL = fmt.Sprintf("%s#issuecomment-%d", feedback.URL, comment.ID)
+		feedback.GeneralIssues = append(feedback.GeneralIssues, comment)
+	}
+
+	if len(pr.Commits.Nodes) > 0 && pr.Commits.Nodes[0].Commit.StatusCheckRollup != nil {
+		for _, context := range pr.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts.Nodes {
+			check := StatusCheck{Required: context.IsRequired}
+			if context.Typename == "StatusContext" {
+				check.Name = context.Context
+				check.Status = "COMPLETED"
+				check.Conclusion = context.State
+				check.DetailsURL = context.TargetURL
+				check.StartedAt = context.CreatedAt
+			} else {
+				check.Name = context.Name
+				check.Status = context.Status
+				check.Conclusion = context.Conclusion
+				check.DetailsURL = context.DetailsURL
+				check.StartedAt = context.StartedAt
+				check.CompletedAt = context.CompletedAt
+				if context.CheckSuite != nil && context.CheckSuite.WorkflowRun != nil {
+					check.WorkflowName = context.CheckSuite.WorkflowRun.Workflow.Name
+				}
+			}
+			if !isFailingConclusion(check.Conclusion) {
+				continue
+			}
+			if runID := extractRunID(check.DetailsURL); runID != "" && strings.Contains(check.DetailsURL, "/actions/runs/") {
+				check.RunID = runID
+				check.CheckCommand = fmt.Sprintf("gh run view %s", runID)
+			}
+			counts.FailingChecks++
+			feedback.StatusChecks = append(feedback.StatusChecks, check)
+		}
+	}
+	// Keep only the newest failures, like the other sections
+	if len(feedback.StatusChecks) > shallowItems {
+		feedback.StatusChecks = feedback.StatusChecks[len(feedback.StatusChecks)-shallowItems:]
+	}
+	measureThreads(feedback)
+
+	return feedback, nil
+}
+
+// shallowThreadShown reports whether a thread would be listed, rather than
+// only counted, by a shallow fetch
+func shallowThreadShown(thread shallowThread, opts *options) bool {
+	if (thread.IsResolved && !opts.includeResolved) || len(thread.Comments.Nodes) == 0 {
+		return false
+	}
+	return !thread.Comments.Nodes[0].IsMinimized || opts.showMinimized
+}
@@ -0,0 +1,46 @@
+package main
+
+import "time"
+
+// filterSince keeps only the feedback created or updated after cutoff, set by
+// --since, and the reviews submitted after it. Threads with a newer reply are
+// kept whole, for context.
+func filterSince(feedback *PRFeedback, cutoff time.Time) {
+	if cutoff.IsZero() {
+		return
+	}
+	keep := func(comments []ReviewComment) []ReviewComment {
+		kept := comments[:0]
+		for _, comment := range comments {
+			if touchedSince(comment, cutoff) {
+				kept = append(kept, comment)
+			}
+		}
+		return kept
+	}
+	feedback.Comments = keep(feedback.Comments)
+	feedback.GeneralIssues = keep(feedback.GeneralIssues)
+
+	// The header's review states would otherwise still count older reviews
+	reviews := feedback.Reviews[:0]
+	for _, review := range feedback.Reviews {
+		if t, err := parseTime(review.SubmittedAt); err == nil && t.After(cutoff) {
+			reviews = append(reviews, review)
+		}
+	}
+	feedback.Reviews = reviews
+}
+
+func touchedSince(comment ReviewComment, cutoff time.Time) bool {
+	for _, stamp := range []string{comment.CreatedAt, comment.UpdatedAt} {
+		if t, err := parseTime(stamp); err == nil && t.After(cutoff) {
+			return true
+		}
+	}
+	for _, reply := range comment.Replies {
+		if touchedSince(reply, cutoff) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// sortOrders are the orders --sort accepts
+var sortOrders = []string{"newest", "oldest", "file", "author"}
+
+// sortFeedback orders the comments and checks for --sort, so output is stable
+// from run to run. Required checks stay ahead of optional ones, and checks
+// have no file or author, so those orders sort them by workflow and name.
+func sortFeedback(feedback *PRFeedback, order string) {
+	for _, comments := range [][]ReviewComment{feedback.Comments, feedback.GeneralIssues} {
+		sort.SliceStable(comments, func(i, j int) bool {
+			a, b := comments[i], comments[j]
+			switch order {
+			case "newest":
+				return a.CreatedAt > b.CreatedAt
+			case "oldest":
+				return a.CreatedAt < b.CreatedAt
+			case "file":
+				if a.Path != b.Path {
+					return a.Path < b.Path
+				}
+				return commentLine(a) < commentLine(b)
+			case "author":
+				if !strings.EqualFold(a.Author, b.Author) {
+					return strings.ToLower(a.Author) < strings.ToLower(b.Author)
+				}
+				return a.CreatedAt < b.CreatedAt
+			}
+			return false
+		})
+	}
+
+	checks := feedback.StatusChecks
+	sort.SliceStable(checks, func(i, j int) bool {
+		a, b := checks[i], checks[j]
+		if a.Required != b.Required {
+			return a.Required
+		}
+		switch order {
+		case "newest":
+			return checkTime(a) > checkTime(b)
+		case "oldest":
+			return checkTime(a) < checkTime(b)
+		}
+		if a.WorkflowName != b.WorkflowName {
+			return a.WorkflowName < b.WorkflowName
+		}
+		return a.Name < b.Name
+	})
+}
+
+func commentLine(comment ReviewComment) int {
+	if comment.Line != nil {
+		return *comment.Line
+	}
+	if comment.OriginalLine != nil {
+		return *comment.OriginalLine
+	}
+	return 0
+}
+
+// checkTime is when a check finished, or started if it's still running
+func checkTime(check StatusCheck) string {
+	if check.CompletedAt != "" {
+		return check.CompletedAt
+	}
+	return check.StartedAt
+}
+
+// limitFeedback keeps at most limit comments, counting general comments
+// before line comments as they're shown, and at most limit checks
+func limitFeedback(feedback *PRFeedback, limit int) {
+	if limit <= 0 {
+		return
+	}
+	if len(feedback.GeneralIssues) >= limit {
+		feedback.GeneralIssues = feedback.GeneralIssues[:limit]
+		feedback.Comments = nil
+	} else if len(feedback.Comments) > limit-len(feedback.GeneralIssues) {
+		feedback.Comments = feedback.Comments[:limit-len(feedback.GeneralIssues)]
+	}
+	if len(feedback.StatusChecks) > limit {
+		feedback.StatusChecks = feedback.StatusChecks[:limit]
+	}
+}
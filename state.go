@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PRState is the local working state for a PR's triage session. It lives in
+// .git/gh-pr-feedback/<pr>/state.json and is never sent to GitHub.
+type PRState struct {
+	Repo       string         `json:"repo,omitempty"`
+	LastViewed int            `json:"last_viewed,omitempty"`
+	Acked      []int          `json:"acked,omitempty"`
+	Notes      map[int]string `json:"notes,omitempty"`
+	// Assignments maps threads handed off with `assign` to the assignee
+	Assignments map[int]string `json:"assignments,omitempty"`
+	// Checkpoint is recorded by the pre-push hook from install-hooks
+	Checkpoint *pushCheckpoint `json:"checkpoint,omitempty"`
+	UpdatedAt  string          `json:"updated_at,omitempty"`
+}
+
+// stateDir returns the directory holding local state for a PR
+func stateDir(prNumber int) (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository")
+	}
+	gitDir := strings.TrimSpace(string(output))
+	return filepath.Join(gitDir, "gh-pr-feedback", strconv.Itoa(prNumber)), nil
+}
+
+// loadPRState reads the saved state for a PR, returning empty state if none
+// has been saved yet or it belongs to a different repository.
+func loadPRState(repo string, prNumber int) (*PRState, error) {
+	dir, err := stateDir(prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &PRState{Repo: repo}
+	data, err := os.ReadFile(filepath.Join(dir, "state.json"))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	var saved PRState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	if saved.Repo != "" && saved.Repo != repo {
+		return state, nil
+	}
+	saved.Repo = repo
+	return &saved, nil
+}
+
+func savePRState(prNumber int, state *PRState) error {
+	dir, err := stateDir(prNumber)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	state.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "state.json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
+
+func (s *PRState) isAcked(id int) bool {
+	for _, acked := range s.Acked {
+		if acked == id {
+			return true
+		}
+	}
+	return false
+}
+
+// runAck marks a thread as acknowledged so `--resume` skips it, and records it
+// as the last thread viewed.
+func runAck(args []string) {
+	var commentID int
+	var undo bool
+	var rest []string
+	for _, arg := range args {
+		if arg == "--undo" {
+			undo = true
+			continue
+		}
+		if commentID == 0 && !strings.HasPrefix(arg, "-") {
+			if id, err := strconv.Atoi(arg); err == nil && id > 0 {
+				commentID = id
+				continue
+			}
+		}
+		rest = append(rest, arg)
+	}
+	if commentID == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback ack <comment-id> [--undo] [pr-number]\n")
+		os.Exit(1)
+	}
+
+	opts := parseArgs(rest)
+	resolvePR(opts)
+
+	state, err := loadPRState(opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+		os.Exit(1)
+	}
+
+	if undo {
+		var acked []int
+		for _, id := range state.Acked {
+			if id != commentID {
+				acked = append(acked, id)
+			}
+		}
+		state.Acked = acked
+	} else if !state.isAcked(commentID) {
+		state.Acked = append(state.Acked, commentID)
+	}
+	state.LastViewed = commentID
+
+	if err := savePRState(opts.prNumber, state); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving state: %v\n", err)
+		os.Exit(1)
+	}
+
+	if undo {
+		fmt.Printf("Un-acknowledged comment %d on PR #%d\n", commentID, opts.prNumber)
+	} else {
+		fmt.Printf("Acknowledged comment %d on PR #%d\n", commentID, opts.prNumber)
+	}
+}
+
+// applyResume drops acknowledged threads and rotates the remaining review
+// or general comments so the list starts just after the last one viewed. It
+// returns the number of threads hidden.
+func applyResume(feedback *PRFeedback, state *PRState) int {
+	hidden := 0
+	filter := func(comments []ReviewComment) []ReviewComment {
+		var kept []ReviewComment
+		for _, comment := range comments {
+			if state.isAcked(comment.ID) {
+				hidden++
+				continue
+			}
+			kept = append(kept, comment)
+		}
+		return kept
+	}
+
+	// Rotate before filtering so the last viewed position is still known
+	// even when that thread has since been acknowledged.
+	rotate := func(comments []ReviewComment) []ReviewComment {
+		for i, comment := range comments {
+			if comment.ID == state.LastViewed {
+				rotated := append([]ReviewComment{}, comments[i+1:]...)
+				return append(rotated, comments[:i+1]...)
+			}
+		}
+		return comments
+	}
+
+	feedback.Comments = filter(rotate(feedback.Comments))
+	feedback.GeneralIssues = filter(rotate(feedback.GeneralIssues))
+	return hidden
+}
+
+// runNote stores, prints or deletes the private note attached to a thread
+func runNote(args []string) {
+	var commentID int
+	var message string
+	var hasMessage, remove bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "-m" || arg == "--message" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			message = args[i+1]
+			hasMessage = true
+			i++
+			continue
+		}
+		if arg == "--delete" {
+			remove = true
+			continue
+		}
+		if commentID == 0 && !strings.HasPrefix(arg, "-") {
+			if id, err := strconv.Atoi(arg); err == nil && id > 0 {
+				commentID = id
+				continue
+			}
+		}
+		rest = append(rest, arg)
+	}
+	if commentID == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback note <comment-id> [-m message | --delete] [pr-number]\n")
+		os.Exit(1)
+	}
+
+	opts := parseArgs(rest)
+	resolvePR(opts)
+
+	state, err := loadPRState(opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Without -m or --delete just show the current note
+	if !hasMessage && !remove {
+		if note, ok := state.Notes[commentID]; ok {
+			fmt.Println(note)
+		} else {
+			fmt.Fprintf(os.Stderr, "No note for comment %d\n", commentID)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if state.Notes == nil {
+		state.Notes = make(map[int]string)
+	}
+	if remove || strings.TrimSpace(message) == "" {
+		delete(state.Notes, commentID)
+	} else {
+		state.Notes[commentID] = message
+	}
+
+	if err := savePRState(opts.prNumber, state); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving state: %v\n", err)
+		os.Exit(1)
+	}
+
+	if _, ok := state.Notes[commentID]; ok {
+		fmt.Printf("Saved note for comment %d on PR #%d\n", commentID, opts.prNumber)
+	} else {
+		fmt.Printf("Removed note for comment %d on PR #%d\n", commentID, opts.prNumber)
+	}
+}
+
+// attachNotes copies saved notes onto the comments they belong to
+func attachNotes(feedback *PRFeedback, state *PRState) {
+	for i := range feedback.Comments {
+		feedback.Comments[i].Note = state.Notes[feedback.Comments[i].ID]
+	}
+	for i := range feedback.GeneralIssues {
+		feedback.GeneralIssues[i].Note = state.Notes[feedback.GeneralIssues[i].ID]
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// summarizeInstructions precede the feedback piped to the summarize command,
+// so a plain model runner like `ollama run llama3` or `llm` can be used as is
+const summarizeInstructions = `Summarize the outstanding review feedback on this pull request in exactly
+5 short Markdown bullet points, most important first. Reply with only the
+bullets.
+
+`
+
+// summarizeCommand returns the command --summarize pipes the feedback
+// through: --summarize-command, then summarize_command from a local config.
+// Like analyzer commands, a config fetched from the remote repository can't
+// choose what runs locally.
+func summarizeCommand(opts *options, config *Config) (string, error) {
+	if opts.summarizeCmd != "" {
+		return opts.summarizeCmd, nil
+	}
+	if config.SummarizeCommand != "" {
+		if !config.local {
+			return "", fmt.Errorf("summarize_command only runs from a local %s; pass --summarize-command instead", configPath)
+		}
+		return config.SummarizeCommand, nil
+	}
+	return "", fmt.Errorf("--summarize needs a command, e.g. --summarize-command \"ollama run llama3\" or summarize_command in %s", configPath)
+}
+
+// summarizeFeedback runs command with the feedback in prompt format on
+// stdin and returns what it prints, the generated summary
+func summarizeFeedback(feedback *PRFeedback, command string) (string, error) {
+	var input bytes.Buffer
+	input.WriteString(summarizeInstructions)
+	writePrompt(&input, feedback)
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = &input
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("summarize command failed: %w", err)
+	}
+	summary := strings.TrimSpace(string(output))
+	if summary == "" {
+		return "", fmt.Errorf("summarize command printed nothing")
+	}
+	return summary, nil
+}
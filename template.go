@@ -0,0 +1,60 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// templateFuncs are available to --template on top of the text/template
+// builtins, named after the ones gh's own --template offers
+var templateFuncs = template.FuncMap{
+	"join": func(sep string, items []string) string { return strings.Join(items, sep) },
+	"truncate": func(width int, s string) string {
+		return runewidth.Truncate(firstLine(s), width, "…")
+	},
+	"timeago": func(timestamp string) string {
+		t, err := parseTime(timestamp)
+		if err != nil {
+			return timestamp
+		}
+		return formatTimeAgo(now().Sub(t))
+	},
+}
+
+// parseFeedbackTemplate parses a --template. As the template is usually
+// given in single quotes on the command line, \n and \t outside of actions
+// are taken as a newline and a tab.
+func parseFeedbackTemplate(text string) (*template.Template, error) {
+	var unescaped strings.Builder
+	escapes := strings.NewReplacer(`\n`, "\n", `\t`, "\t")
+	for text != "" {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			start = len(text)
+		}
+		unescaped.WriteString(escapes.Replace(text[:start]))
+		text = text[start:]
+		end := strings.Index(text, "}}")
+		if end < 0 {
+			end = len(text)
+		} else {
+			end += len("}}")
+		}
+		unescaped.WriteString(text[:end])
+		text = text[end:]
+	}
+	return template.New("feedback").Funcs(templateFuncs).Parse(unescaped.String())
+}
+
+// writeTemplate renders the feedback with a --template, with PRFeedback as
+// the root, e.g. {{range .Comments}}{{.Path}}:{{.Line}}\n{{end}}
+func writeTemplate(w io.Writer, text string, feedback *PRFeedback) error {
+	tmpl, err := parseFeedbackTemplate(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, feedback)
+}
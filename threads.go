@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// reviewThread is a PR review thread as reported by the GraphQL API, which
+// unlike REST knows whether a thread has been resolved.
+type reviewThread struct {
+	ID         string
+	IsResolved bool
+	IsOutdated bool
+	ResolvedBy string
+	// Comment is the thread's first comment
+	Comment ReviewComment
+	// Comments counts the comments in the thread, including the first
+	Comments      int
+	LastCommentAt string
+}
+
+// scopeNotice makes sure the missing scopes warning is only printed once when
+// several PRs are fetched
+var scopeNotice sync.Once
+
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          isResolved
+          isOutdated
+          path
+          line
+          originalLine
+          resolvedBy { login }
+          comments(first: 1) {
+            totalCount
+            nodes { databaseId body author { login } createdAt updatedAt }
+          }
+          lastComment: comments(last: 1) {
+            nodes { createdAt }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func createGraphQLClient() *api.GraphQLClient {
+	client, err := api.NewGraphQLClient(clientOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating GitHub GraphQL client: %v\n", err)
+		os.Exit(1)
+	}
+
+	return client
+}
+
+// markResolvedThreads sets the state of the PR's review comments from their
+// threads, which REST doesn't report, and drops the resolved ones unless
+// --include-resolved is set. Without thread data every comment is kept.
+func markResolvedThreads(feedback *PRFeedback, opts *options) {
+	if len(feedback.Comments) == 0 {
+		return
+	}
+	threads := lookupReviewThreads(opts, feedback.Repo, feedback.PRNumber)
+	if threads == nil {
+		return
+	}
+	resolved := make(map[int]bool)
+	for _, thread := range threads {
+		if thread.IsResolved {
+			resolved[thread.Comment.ID] = true
+		}
+	}
+
+	kept := feedback.Comments[:0]
+	for _, comment := range feedback.Comments {
+		if resolved[comment.ID] {
+			if !opts.includeResolved {
+				continue
+			}
+			comment.State = "resolved"
+		}
+		kept = append(kept, comment)
+	}
+	feedback.Comments = kept
+}
+
+// lookupReviewThreads fetches the PR's review threads for telling resolved
+// ones apart, reporting why when they can't be read. It returns nil then,
+// unless --strict makes that fatal.
+func lookupReviewThreads(opts *options, repo string, prNumber int) []reviewThread {
+	threads, err := fetchReviewThreads(createGraphQLClient(), repo, prNumber)
+	if err != nil {
+		switch {
+		case opts.strict:
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		case isScopeError(err):
+			scopeNotice.Do(func() {
+				warnf("the token can't read review threads, so resolved threads are shown too (run `gh auth refresh --scopes repo`, or pass --strict to fail)")
+			})
+		case !budgetSkipped(err):
+			warnf("couldn't tell resolved threads apart, showing all of them: %v", err)
+		}
+		return nil
+	}
+	return threads
+}
+
+// isScopeError reports whether a GraphQL request failed because the token
+// isn't allowed to make it, rather than for a reason worth retrying
+func isScopeError(err error) bool {
+	var gqlErr *api.GraphQLError
+	if errors.As(err, &gqlErr) {
+		for _, item := range gqlErr.Errors {
+			if item.Type == "INSUFFICIENT_SCOPES" || item.Type == "FORBIDDEN" {
+				return true
+			}
+		}
+	}
+	var httpErr *api.HTTPError
+	return errors.As(err, &httpErr) && (httpErr.StatusCode == 401 || httpErr.StatusCode == 403)
+}
+
+// fetchReviewThreads returns every review thread on the PR, resolved or not
+func fetchReviewThreads(client *api.GraphQLClient, repo string, prNumber int) ([]reviewThread, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repository %q", repo)
+	}
+
+	var threads []reviewThread
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"name":   name,
+		"number": prNumber,
+		"cursor": nil,
+	}
+	for {
+		var response struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							ID           string `json:"id"`
+							IsResolved   bool   `json:"isResolved"`
+							IsOutdated   bool   `json:"isOutdated"`
+							Path         string `json:"path"`
+							Line         *int   `json:"line"`
+							OriginalLine *int   `json:"originalLine"`
+							ResolvedBy   *struct {
+								Login string `json:"login"`
+							} `json:"resolvedBy"`
+							Comments struct {
+								TotalCount int `json:"totalCount"`
+								Nodes      []struct {
+									DatabaseID int    `json:"databaseId"`
+									Body       string `json:"body"`
+									Author     *struct {
+										Login string `json:"login"`
+									} `json:"author"`
+									CreatedAt string `json:"createdAt"`
+									UpdatedAt string `json:"updatedAt"`
+								} `json:"nodes"`
+							} `json:"comments"`
+							LastComment struct {
+								Nodes []struct {
+									CreatedAt string `json:"createdAt"`
+								} `json:"nodes"`
+							} `json:"lastComment"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := client.Do(reviewThreadsQuery, variables, &response); err != nil {
+			return nil, fmt.Errorf("failed to fetch review threads: %w", err)
+		}
+
+		page := response.Repository.PullRequest.ReviewThreads
+		for _, node := range page.Nodes {
+			thread := reviewThread{
+				ID:         node.ID,
+				IsResolved: node.IsResolved,
+				IsOutdated: node.IsOutdated,
+				Comments:   node.Comments.TotalCount,
+			}
+			if len(node.LastComment.Nodes) > 0 {
+				thread.LastCommentAt = node.LastComment.Nodes[0].CreatedAt
+			}
+			if node.ResolvedBy != nil {
+				thread.ResolvedBy = node.ResolvedBy.Login
+			}
+			thread.Comment = ReviewComment{
+				Path:         node.Path,
+				Line:         node.Line,
+				OriginalLine: node.OriginalLine,
+				Outdated:     node.IsOutdated,
+			}
+			if len(node.Comments.Nodes) > 0 {
+				root := node.Comments.Nodes[0]
+				thread.Comment.ID = root.DatabaseID
+				thread.Comment.Body = root.Body
+				thread.Comment.CreatedAt = root.CreatedAt
+				thread.Comment.UpdatedAt = root.UpdatedAt
+				if root.Author != nil {
+					thread.Comment.Author = root.Author.Login
+				}
+			}
+			threads = append(threads, thread)
+		}
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = page.PageInfo.EndCursor
+	}
+
+	return threads, nil
+}
+
+// setThreadResolved resolves or unresolves a review thread
+func setThreadResolved(client *api.GraphQLClient, threadID string, resolved bool) error {
+	mutation := `mutation($id: ID!) { unresolveReviewThread(input: {threadId: $id}) { thread { id } } }`
+	if resolved {
+		mutation = `mutation($id: ID!) { resolveReviewThread(input: {threadId: $id}) { thread { id } } }`
+	}
+	var response struct{}
+	if err := client.Do(mutation, map[string]interface{}{"id": threadID}, &response); err != nil {
+		return fmt.Errorf("failed to update thread %s: %w", threadID, err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// todoItem matches a checklist line written by `todo`, capturing whether
+// it's checked and the thread it stands for
+var todoItem = regexp.MustCompile(`^\s*- \[([ xX])\] .*<!-- thread:(\d+) -->\s*$`)
+
+// runTodo writes the PR's review threads as a Markdown checklist, one item
+// per thread, checked when the thread is resolved. With --push, items checked
+// in the file since are resolved on GitHub first, so the checklist can be
+// worked through from an editor.
+func runTodo(args []string) {
+	var output string
+	var push, dryRun bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "-o", "--output":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a file\n", arg)
+				os.Exit(1)
+			}
+			output = args[i+1]
+			i++
+		case "--push":
+			push = true
+		case "--dry-run":
+			dryRun = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	opts := parseArgs(rest)
+	opts.includeResolved = true
+	client := resolvePR(opts)
+	if output == "" {
+		output = fmt.Sprintf("TODO-%d.md", opts.prNumber)
+	}
+
+	if push {
+		graphql := createGraphQLClient()
+		threads, err := fetchReviewThreads(graphql, opts.repoName, opts.prNumber)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		checked, err := readCheckedTodos(output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		failed := false
+		for _, thread := range threads {
+			if thread.IsResolved || !checked[thread.Comment.ID] {
+				continue
+			}
+			if dryRun {
+				fmt.Printf("Would resolve thread %d: %s\n", thread.Comment.ID, firstLine(thread.Comment.Body))
+				continue
+			}
+			if err := setThreadResolved(graphql, thread.ID, true); err != nil {
+				fmt.Fprintf(os.Stderr, "%s✗%s Thread %d: %v\n", colorRed, colorReset, thread.Comment.ID, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("%s✓%s Resolved thread %d: %s\n", colorGreen, colorReset, thread.Comment.ID, firstLine(thread.Comment.Body))
+		}
+		if dryRun {
+			return
+		}
+		if failed {
+			os.Exit(1)
+		}
+	}
+
+	feedback, err := getPRComments(client, opts, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+	// Private notes go in the checklist too; state is optional when outside
+	// a git repository
+	if state, err := loadPRState(opts.repoName, opts.prNumber); err == nil {
+		attachNotes(feedback, state)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	writeTodo(file, feedback)
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", output, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d thread(s) to %s\n", len(feedback.Comments), output)
+}
+
+// readCheckedTodos returns the threads checked off in a checklist written by
+// `todo`
+func readCheckedTodos(path string) (map[int]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("no checklist to push: %w", err)
+	}
+	defer file.Close()
+
+	checked := make(map[int]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := todoItem.FindStringSubmatch(scanner.Text())
+		if match == nil || match[1] == " " {
+			continue
+		}
+		if id, err := strconv.Atoi(match[2]); err == nil {
+			checked[id] = true
+		}
+	}
+	return checked, scanner.Err()
+}
+
+// writeTodo writes one checklist item per review thread, with its size so
+// quick wins can be picked first and any private note under it
+func writeTodo(w io.Writer, feedback *PRFeedback) {
+	fmt.Fprintf(w, "# %s #%d\n\n", feedback.Title, feedback.PRNumber)
+	fmt.Fprintf(w, "%s\n\n", feedback.URL)
+	fmt.Fprintf(w, "Check items off and run `gh pr-feedback todo --push` to resolve their threads.\n\n")
+
+	for _, comment := range feedback.Comments {
+		mark := " "
+		if comment.State == "resolved" {
+			mark = "x"
+		}
+		location := comment.Path
+		if comment.Line != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+		}
+		var size []string
+		switch {
+		case comment.ReplyCount == 1:
+			size = append(size, "1 reply")
+		case comment.ReplyCount > 1:
+			size = append(size, fmt.Sprintf("%d replies", comment.ReplyCount))
+		}
+		if comment.ReadingSeconds >= 60 {
+			size = append(size, fmt.Sprintf("~%d min read", (comment.ReadingSeconds+30)/60))
+		}
+		line := fmt.Sprintf("- [%s] `%s` @%s: %s", mark, location, comment.Author, firstLine(comment.Body))
+		if len(size) > 0 {
+			line += " _(" + strings.Join(size, ", ") + ")_"
+		}
+		fmt.Fprintf(w, "%s <!-- thread:%d -->\n", line, comment.ID)
+		if comment.Note != "" {
+			fmt.Fprintf(w, "  - _Note: %s_\n", comment.Note)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Topic is a group of related comments, e.g. everything about error handling
+type Topic struct {
+	Name       string `json:"name"`
+	CommentIDs []int  `json:"comment_ids"`
+}
+
+// topicKeywords are the built-in topics, matched in order against comment
+// bodies. Comments matching none of them are clustered by TF-IDF similarity.
+var topicKeywords = []struct {
+	name     string
+	keywords []string
+}{
+	{"error handling", []string{"error", "err", "errors", "panic", "recover", "exception", "wrap", "wrapped"}},
+	{"tests", []string{"test", "tests", "testing", "coverage", "assert", "mock", "fixture"}},
+	{"naming", []string{"name", "naming", "rename", "renamed", "variable", "identifier"}},
+	{"documentation", []string{"doc", "docs", "comment", "comments", "readme", "godoc", "documentation", "typo"}},
+	{"performance", []string{"performance", "slow", "allocation", "allocations", "benchmark", "cache", "n+1", "complexity"}},
+	{"concurrency", []string{"race", "mutex", "lock", "goroutine", "goroutines", "channel", "concurrent", "deadlock", "thread"}},
+	{"security", []string{"security", "secret", "token", "injection", "sanitize", "escape", "xss", "csrf", "vulnerability"}},
+	{"style", []string{"nit", "nitpick", "style", "format", "formatting", "indentation", "whitespace", "lint"}},
+}
+
+// topicSimilarity is the minimum cosine similarity for TF-IDF clustering
+const topicSimilarity = 0.3
+
+// embeddingSimilarity is the minimum cosine similarity when clustering
+// vectors from --topics-command
+const embeddingSimilarity = 0.8
+
+// groupTopics clusters the PR's comments into topics. When command is set it
+// is run with the comments as JSON on stdin and must print a JSON array of
+// {"id": ..., "embedding": [...]} objects used for clustering instead.
+func groupTopics(feedback *PRFeedback, command string) ([]Topic, error) {
+	comments := append(append([]ReviewComment{}, feedback.Comments...), feedback.GeneralIssues...)
+	if len(comments) == 0 {
+		return nil, nil
+	}
+
+	documents := make([][]string, len(comments))
+	for i, comment := range comments {
+		documents[i] = topicTerms(comment.Body)
+	}
+	tfidf := tfidfVectors(documents)
+
+	if command != "" {
+		embeddings, err := runEmbeddingCommand(command, comments)
+		if err != nil {
+			return nil, err
+		}
+		indexes := make([]int, len(comments))
+		for i := range comments {
+			indexes[i] = i
+		}
+		return clusterTopics(comments, indexes, embeddings, tfidf, embeddingSimilarity), nil
+	}
+
+	var topics []Topic
+	var uncategorized []int
+	byName := make(map[string]int)
+	for i, comment := range comments {
+		name := keywordTopic(documents[i])
+		if name == "" {
+			uncategorized = append(uncategorized, i)
+			continue
+		}
+		t, ok := byName[name]
+		if !ok {
+			t = len(topics)
+			topics = append(topics, Topic{Name: name})
+			byName[name] = t
+		}
+		topics[t].CommentIDs = append(topics[t].CommentIDs, comment.ID)
+	}
+
+	topics = append(topics, clusterTopics(comments, uncategorized, tfidf, tfidf, topicSimilarity)...)
+	sortTopics(topics)
+	return topics, nil
+}
+
+// keywordTopic returns the first built-in topic whose keywords appear in terms
+func keywordTopic(terms []string) string {
+	present := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		present[term] = true
+	}
+	for _, topic := range topicKeywords {
+		for _, keyword := range topic.keywords {
+			if present[keyword] {
+				return topic.name
+			}
+		}
+	}
+	return ""
+}
+
+// clusterTopics greedily groups the comments at indexes whose vectors are at
+// least threshold similar, naming each cluster by its strongest TF-IDF terms.
+// Comments that don't cluster with anything end up under "other".
+func clusterTopics(comments []ReviewComment, indexes []int, vectors []map[string]float64, tfidf []map[string]float64, threshold float64) []Topic {
+	var clusters [][]int
+	for _, i := range indexes {
+		placed := false
+		for c, cluster := range clusters {
+			if cosine(vectors[cluster[0]], vectors[i]) >= threshold {
+				clusters[c] = append(cluster, i)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			clusters = append(clusters, []int{i})
+		}
+	}
+
+	var topics []Topic
+	other := Topic{Name: "other"}
+	for _, cluster := range clusters {
+		if len(cluster) == 1 {
+			other.CommentIDs = append(other.CommentIDs, comments[cluster[0]].ID)
+			continue
+		}
+		topic := Topic{Name: clusterLabel(cluster, tfidf)}
+		for _, i := range cluster {
+			topic.CommentIDs = append(topic.CommentIDs, comments[i].ID)
+		}
+		topics = append(topics, topic)
+	}
+	if len(other.CommentIDs) > 0 {
+		topics = append(topics, other)
+	}
+	return topics
+}
+
+// clusterLabel names a cluster after its two highest-weighted terms
+func clusterLabel(cluster []int, tfidf []map[string]float64) string {
+	weights := make(map[string]float64)
+	for _, i := range cluster {
+		for term, weight := range tfidf[i] {
+			weights[term] += weight
+		}
+	}
+	terms := make([]string, 0, len(weights))
+	for term := range weights {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if weights[terms[i]] != weights[terms[j]] {
+			return weights[terms[i]] > weights[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > 2 {
+		terms = terms[:2]
+	}
+	if len(terms) == 0 {
+		return "other"
+	}
+	return strings.Join(terms, ", ")
+}
+
+func sortTopics(topics []Topic) {
+	sort.SliceStable(topics, func(i, j int) bool {
+		// Keep the catch-all group last
+		if (topics[i].Name == "other") != (topics[j].Name == "other") {
+			return topics[j].Name == "other"
+		}
+		return len(topics[i].CommentIDs) > len(topics[j].CommentIDs)
+	})
+}
+
+// stopWords are ignored when weighing terms
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "but": true,
+	"by": true, "can": true, "could": true, "do": true, "does": true, "for": true, "from": true,
+	"here": true, "i": true, "if": true, "in": true, "is": true, "it": true, "its": true, "just": true,
+	"maybe": true, "me": true, "might": true, "not": true, "of": true, "on": true, "or": true,
+	"please": true, "should": true, "so": true, "that": true, "the": true, "this": true, "to": true,
+	"we": true, "what": true, "when": true, "which": true, "why": true, "will": true, "with": true,
+	"would": true, "you": true, "your": true, "there": true, "than": true, "then": true, "also": true,
+	"have": true, "has": true, "was": true, "were": true, "all": true, "any": true, "some": true,
+}
+
+// topicTerms returns the meaningful lower-cased words in a comment body
+func topicTerms(body string) []string {
+	var terms []string
+	for word := range wordSet(body) {
+		if len(word) > 1 && !stopWords[word] {
+			terms = append(terms, word)
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+// tfidfVectors weighs each document's terms by how rare they are across all
+// documents.
+func tfidfVectors(documents [][]string) []map[string]float64 {
+	frequency := make(map[string]int)
+	for _, terms := range documents {
+		for _, term := range terms {
+			frequency[term]++
+		}
+	}
+
+	vectors := make([]map[string]float64, len(documents))
+	for i, terms := range documents {
+		vectors[i] = make(map[string]float64, len(terms))
+		for _, term := range terms {
+			vectors[i][term] = math.Log(float64(len(documents)+1) / float64(frequency[term]))
+		}
+	}
+	return vectors
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for key, value := range a {
+		dot += value * b[key]
+		normA += value * value
+	}
+	for _, value := range b {
+		normB += value * value
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// runEmbeddingCommand asks an external command for an embedding per comment
+func runEmbeddingCommand(command string, comments []ReviewComment) ([]map[string]float64, error) {
+	type input struct {
+		ID   int    `json:"id"`
+		Body string `json:"body"`
+	}
+	inputs := make([]input, len(comments))
+	for i, comment := range comments {
+		inputs[i] = input{ID: comment.ID, Body: comment.Body}
+	}
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("topics command failed: %w", err)
+	}
+
+	var results []struct {
+		ID        int       `json:"id"`
+		Embedding []float64 `json:"embedding"`
+	}
+	if err := json.Unmarshal(output, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse topics command output: %w", err)
+	}
+
+	byID := make(map[int][]float64, len(results))
+	for _, result := range results {
+		byID[result.ID] = result.Embedding
+	}
+	vectors := make([]map[string]float64, len(comments))
+	for i, comment := range comments {
+		vectors[i] = make(map[string]float64)
+		for d, value := range byID[comment.ID] {
+			vectors[i][fmt.Sprint(d)] = value
+		}
+	}
+	return vectors, nil
+}
+
+func printTopics(feedback *PRFeedback) {
+	locations := make(map[int]string)
+	for _, comment := range feedback.Comments {
+		location := comment.Path
+		if comment.Line != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+		}
+		locations[comment.ID] = location
+	}
+	for _, comment := range feedback.GeneralIssues {
+		locations[comment.ID] = comment.Author
+	}
+
+	fmt.Printf("%sTopics%s\n", colorBold, colorReset)
+	for _, topic := range feedback.Topics {
+		var where []string
+		for _, id := range topic.CommentIDs {
+			if len(where) == 4 {
+				where = append(where, "…")
+				break
+			}
+			where = append(where, locations[id])
+		}
+		fmt.Printf("  %s%-16s%s %2d  %s%s%s\n", colorCyan, topic.Name, colorReset, len(topic.CommentIDs), colorGray, strings.Join(where, ", "), colorReset)
+	}
+	fmt.Println()
+}
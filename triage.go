@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// triagePlan is the answer file applied by `triage --plan`. Threads are
+// identified by the ID of their first comment, as shown by gh pr-feedback.
+type triagePlan struct {
+	Resolve   []int         `yaml:"resolve"`
+	Unresolve []int         `yaml:"unresolve"`
+	Reply     []triageReply `yaml:"reply"`
+	// Comment is posted as a general comment on the PR
+	Comment string   `yaml:"comment"`
+	Rerun   []string `yaml:"rerun"`
+}
+
+type triageReply struct {
+	ID   int    `yaml:"id"`
+	Body string `yaml:"body"`
+}
+
+// triageAction is one step of a plan, run after the whole plan validated
+type triageAction struct {
+	description string
+	run         func() error
+}
+
+// runTriage applies a plan of replies, resolutions and check reruns. The
+// whole plan is checked against the PR before anything is changed; once
+// applying starts every action is attempted and failures are reported at
+// the end.
+func runTriage(args []string) {
+	var planPath string
+	var dryRun, failedJobsOnly bool
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--plan":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --plan requires a file\n")
+				os.Exit(1)
+			}
+			planPath = args[i+1]
+			i++
+		case "--dry-run":
+			dryRun = true
+		case "--failed-jobs-only":
+			failedJobsOnly = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if planPath == "" {
+		fmt.Fprintf(os.Stderr, "Usage: gh pr-feedback triage --plan <plan.yml> [--dry-run] [--failed-jobs-only] [pr-number]\n")
+		os.Exit(1)
+	}
+	plan, err := loadTriagePlan(planPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := parseArgs(rest)
+	client := resolvePR(opts)
+	gql := createGraphQLClient()
+
+	actions, problems := planTriage(client, gql, opts, plan, failedJobsOnly)
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: the plan doesn't match PR #%d, nothing was changed:\n", opts.prNumber)
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		os.Exit(1)
+	}
+	if len(actions) == 0 {
+		fmt.Println("Nothing to do")
+		return
+	}
+
+	if dryRun {
+		for _, action := range actions {
+			fmt.Printf("%s•%s %s\n", colorGray, colorReset, action.description)
+		}
+		fmt.Printf("\nWould apply %d action(s) to PR #%d\n", len(actions), opts.prNumber)
+		return
+	}
+
+	var failed []string
+	for _, action := range actions {
+		if err := action.run(); err != nil {
+			fmt.Printf("%s✗%s %s: %v\n", colorRed, colorReset, action.description, err)
+			failed = append(failed, action.description)
+			continue
+		}
+		fmt.Printf("%s✓%s %s\n", colorGreen, colorReset, action.description)
+	}
+
+	fmt.Printf("\nApplied %d of %d action(s) to PR #%d\n", len(actions)-len(failed), len(actions), opts.prNumber)
+	if len(failed) > 0 {
+		fmt.Fprintf(os.Stderr, "%d action(s) failed, rerun with a plan containing only: %s\n", len(failed), strings.Join(failed, "; "))
+		os.Exit(1)
+	}
+}
+
+func loadTriagePlan(path string) (*triagePlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	var plan triagePlan
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&plan); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &plan, nil
+}
+
+// planTriage turns the plan into actions, in the order replies, resolutions,
+// the general comment and reruns, so replies land before their threads are
+// collapsed. Reruns name a check, which reruns its job, or a workflow, which
+// reruns the whole run or with failedJobsOnly just its failed jobs. It
+// returns every problem found rather than stopping at the first.
+func planTriage(client *api.RESTClient, gql *api.GraphQLClient, opts *options, plan *triagePlan, failedJobsOnly bool) ([]triageAction, []string) {
+	var actions []triageAction
+	var problems []string
+
+	threads := make(map[int]reviewThread)
+	if len(plan.Resolve) > 0 || len(plan.Unresolve) > 0 || len(plan.Reply) > 0 {
+		list, err := fetchReviewThreads(gql, opts.repoName, opts.prNumber)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		for _, thread := range list {
+			threads[thread.Comment.ID] = thread
+		}
+	}
+	lookup := func(id int) (reviewThread, bool) {
+		thread, ok := threads[id]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("no review thread starts with comment %d", id))
+		}
+		return thread, ok
+	}
+
+	for _, reply := range plan.Reply {
+		if strings.TrimSpace(reply.Body) == "" {
+			problems = append(problems, fmt.Sprintf("reply to %d has no body", reply.ID))
+			continue
+		}
+		if _, ok := lookup(reply.ID); !ok {
+			continue
+		}
+		actions = append(actions, triageAction{
+			description: fmt.Sprintf("reply to %d", reply.ID),
+			run: func() error {
+				return postComment(client, opts.repoName, opts.prNumber, reply.ID, reply.Body)
+			},
+		})
+	}
+
+	unresolve := make(map[int]bool)
+	for _, id := range plan.Unresolve {
+		unresolve[id] = true
+	}
+	addResolve := func(id int, resolved bool) {
+		thread, ok := lookup(id)
+		if !ok {
+			return
+		}
+		verb := "resolve"
+		if !resolved {
+			verb = "unresolve"
+		}
+		if thread.IsResolved == resolved {
+			warnf("thread %d is already %sd", id, verb)
+			return
+		}
+		actions = append(actions, triageAction{
+			description: fmt.Sprintf("%s %d", verb, id),
+			run: func() error {
+				return setThreadResolved(gql, thread.ID, resolved)
+			},
+		})
+	}
+	for _, id := range plan.Resolve {
+		if unresolve[id] {
+			problems = append(problems, fmt.Sprintf("thread %d is both resolved and unresolved", id))
+			continue
+		}
+		addResolve(id, true)
+	}
+	for _, id := range plan.Unresolve {
+		addResolve(id, false)
+	}
+
+	if strings.TrimSpace(plan.Comment) != "" {
+		actions = append(actions, triageAction{
+			description: "comment on the PR",
+			run: func() error {
+				return postComment(client, opts.repoName, opts.prNumber, 0, plan.Comment)
+			},
+		})
+	}
+
+	rerunRuns := make(map[string]bool)
+	for _, name := range plan.Rerun {
+		jobID, err := findJobID(opts.repoName, opts.prNumber, name)
+		if err == nil {
+			actions = append(actions, triageAction{
+				description: fmt.Sprintf("rerun %s", name),
+				run: func() error {
+					return client.Post(fmt.Sprintf("repos/%s/actions/jobs/%s/rerun", opts.repoName, jobID), nil, nil)
+				},
+			})
+			continue
+		}
+
+		// Not a check, so it may name a whole workflow
+		runID, workflowErr := findWorkflowRunID(opts.repoName, opts.prNumber, name)
+		if workflowErr != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if rerunRuns[runID] {
+			continue
+		}
+		rerunRuns[runID] = true
+		description, endpoint := fmt.Sprintf("rerun workflow %s", name), "rerun"
+		if failedJobsOnly {
+			description, endpoint = fmt.Sprintf("rerun failed jobs of %s", name), "rerun-failed-jobs"
+		}
+		actions = append(actions, triageAction{
+			description: description,
+			run: func() error {
+				return client.Post(fmt.Sprintf("repos/%s/actions/runs/%s/%s", opts.repoName, runID, endpoint), nil, nil)
+			},
+		})
+	}
+
+	return actions, problems
+}
+
+// postComment replies to the review thread started by replyTo, or posts a
+// general PR comment when replyTo is 0
+func postComment(client *api.RESTClient, repo string, prNumber, replyTo int, body string) error {
+	data, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("repos/%s/issues/%d/comments", repo, prNumber)
+	if replyTo != 0 {
+		endpoint = fmt.Sprintf("repos/%s/pulls/%d/comments/%d/replies", repo, prNumber, replyTo)
+	}
+	return client.Post(endpoint, bytes.NewReader(data), nil)
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/browser"
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// tuiItem is one thread in the interactive view
+type tuiItem struct {
+	comment  ReviewComment
+	threadID string
+	general  bool
+	resolved bool
+	replies  int
+	pending  string
+}
+
+// tuiResult reports a background mutation finishing
+type tuiResult struct {
+	index  int
+	action string
+	err    error
+}
+
+type tui struct {
+	client   *api.RESTClient
+	gql      *api.GraphQLClient
+	feedback *PRFeedback
+	// state records the position for --resume; nil outside a git repository
+	state  *PRState
+	items  []*tuiItem
+	cursor int
+	offset int
+	status string
+	// input holds the reply being typed; replying is set while typing
+	input    []rune
+	replying bool
+	results  chan tuiResult
+	// inflight counts mutations still running in the background, which
+	// are waited for on quit
+	inflight int
+	// failures are the mutations that failed after quitting, reported once
+	// the terminal is restored
+	failures []string
+}
+
+// runTUI shows the PR's threads in an interactive list where they can be
+// replied to, resolved, opened and copied without leaving the terminal.
+func runTUI(args []string) {
+	opts := parseArgs(args)
+	client := resolvePR(opts)
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Fprintf(os.Stderr, "Error: tui needs an interactive terminal\n")
+		os.Exit(1)
+	}
+
+	feedback, err := getPRFeedback(client, opts, opts.repoName, opts.prNumber)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching PR feedback: %v\n", err)
+		os.Exit(1)
+	}
+
+	ui := &tui{
+		client:   client,
+		gql:      createGraphQLClient(),
+		feedback: feedback,
+		results:  make(chan tuiResult),
+	}
+
+	// Pick up after the last thread viewed, here or with --queue
+	prState, err := loadPRState(opts.repoName, opts.prNumber)
+	if err == nil {
+		ui.state = prState
+	} else if opts.resume {
+		fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+		os.Exit(1)
+	}
+	resumeInComments := false
+	if opts.resume {
+		for _, comment := range feedback.Comments {
+			resumeInComments = resumeInComments || comment.ID == prState.LastViewed
+		}
+		hidden := applyResume(feedback, prState)
+		ui.status = fmt.Sprintf("Resuming (%d acknowledged thread(s) hidden)", hidden)
+	}
+
+	// Resolving needs the GraphQL thread IDs behind the REST comments
+	threadIDs := make(map[int]string)
+	if threads, err := fetchReviewThreads(ui.gql, opts.repoName, opts.prNumber); err == nil {
+		for _, thread := range threads {
+			threadIDs[thread.Comment.ID] = thread.ID
+		}
+	}
+	for _, comment := range feedback.GeneralIssues {
+		ui.items = append(ui.items, &tuiItem{comment: comment, general: true})
+	}
+	for _, comment := range feedback.Comments {
+		// ReplyCount survives --no-replies dropping the replies themselves
+		ui.items = append(ui.items, &tuiItem{
+			comment:  comment,
+			threadID: threadIDs[comment.ID],
+			resolved: comment.State == "resolved",
+			replies:  max(len(comment.Replies), comment.ReplyCount),
+		})
+	}
+	if len(ui.items) == 0 {
+		fmt.Println("No unresolved feedback")
+		return
+	}
+	// Line comments follow the general ones, and were rotated to start
+	// after the last one viewed
+	if resumeInComments && len(feedback.Comments) > 0 {
+		ui.cursor = len(feedback.GeneralIssues)
+	}
+
+	if !ansiEnabled {
+		fmt.Fprintf(os.Stderr, "Error: tui needs a terminal that supports ANSI escape codes\n")
+		os.Exit(1)
+	}
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// Use the alternate screen so the shell is left as it was
+	fmt.Print("\033[?1049h\033[?25l")
+	ui.run()
+	fmt.Print("\033[?25h\033[?1049l")
+	term.Restore(int(os.Stdin.Fd()), state)
+
+	for _, failure := range ui.failures {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", failure)
+	}
+	if len(ui.failures) > 0 {
+		os.Exit(1)
+	}
+}
+
+func (ui *tui) run() {
+	keys := make(chan []byte)
+	go func() {
+		for {
+			buf := make([]byte, 16)
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- buf[:n]
+		}
+	}()
+
+	ui.render()
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok || !ui.handleKey(key) {
+				ui.drain()
+				return
+			}
+		case result := <-ui.results:
+			ui.finish(result)
+		}
+		ui.render()
+	}
+}
+
+// handleKey applies a key press, returning false when the user quits
+func (ui *tui) handleKey(key []byte) bool {
+	if ui.replying {
+		ui.handleInput(key)
+		return true
+	}
+
+	item := ui.items[ui.cursor]
+	ui.status = ""
+	switch string(key) {
+	case "q", "\x03":
+		return false
+	case "j", "\x1b[B":
+		if ui.cursor < len(ui.items)-1 {
+			ui.markViewed(item)
+			ui.cursor++
+		}
+	case "k", "\x1b[A":
+		if ui.cursor > 0 {
+			ui.markViewed(item)
+			ui.cursor--
+		}
+	case "r":
+		ui.replying = true
+		ui.input = nil
+	case "R":
+		if item.general || item.threadID == "" {
+			ui.status = "Only review threads can be resolved"
+			break
+		}
+		// Update optimistically; finish reverts it if the mutation fails
+		item.resolved = !item.resolved
+		item.pending = "resolving"
+		index, resolved := ui.cursor, item.resolved
+		ui.inflight++
+		go func() {
+			ui.results <- tuiResult{index: index, action: "resolve", err: setThreadResolved(ui.gql, item.threadID, resolved)}
+		}()
+	case "o":
+		if err := browser.New("", io.Discard, io.Discard).Browse(ui.url(item)); err != nil {
+			ui.status = "Failed to open browser: " + err.Error()
+		}
+	case "y":
+		osc52.New(ui.url(item)).WriteTo(os.Stderr)
+		ui.status = "Copied " + ui.url(item)
+	}
+	return true
+}
+
+// handleInput edits the reply being typed and sends it on enter
+func (ui *tui) handleInput(key []byte) {
+	switch string(key) {
+	case "\x1b", "\x03":
+		ui.replying = false
+		ui.status = "Reply cancelled"
+	case "\r":
+		ui.replying = false
+		body := strings.TrimSpace(string(ui.input))
+		if body == "" {
+			ui.status = "Reply cancelled"
+			return
+		}
+		item := ui.items[ui.cursor]
+		item.replies++
+		item.pending = "replying"
+		index := ui.cursor
+		ui.inflight++
+		go func() {
+			ui.results <- tuiResult{index: index, action: "reply", err: ui.reply(item, body)}
+		}()
+	case "\x7f", "\b":
+		if len(ui.input) > 0 {
+			ui.input = ui.input[:len(ui.input)-1]
+		}
+	default:
+		if key[0] >= 0x20 {
+			ui.input = append(ui.input, []rune(string(key))...)
+		}
+	}
+}
+
+// drain waits for the mutations still running when the user quits, so none
+// are dropped, keeping the ones that failed to report afterwards
+func (ui *tui) drain() {
+	for ui.inflight > 0 {
+		ui.status = fmt.Sprintf("Waiting for %d pending change(s)…", ui.inflight)
+		ui.render()
+		result := <-ui.results
+		ui.finish(result)
+		if result.err != nil {
+			comment := ui.items[result.index].comment
+			ui.failures = append(ui.failures, fmt.Sprintf("failed to %s comment %d: %v", result.action, comment.ID, result.err))
+		}
+	}
+}
+
+// finish records the outcome of a background mutation
+func (ui *tui) finish(result tuiResult) {
+	ui.inflight--
+	item := ui.items[result.index]
+	item.pending = ""
+	if result.err == nil {
+		return
+	}
+	switch result.action {
+	case "resolve":
+		item.resolved = !item.resolved
+	case "reply":
+		item.replies--
+	}
+	ui.status = fmt.Sprintf("Failed to %s: %v", result.action, result.err)
+}
+
+// markViewed records a thread the user has moved on from, so --resume
+// continues with the one after it
+func (ui *tui) markViewed(item *tuiItem) {
+	if ui.state == nil || ui.state.LastViewed == item.comment.ID {
+		return
+	}
+	ui.state.LastViewed = item.comment.ID
+	if err := savePRState(ui.feedback.PRNumber, ui.state); err != nil {
+		ui.status = "Failed to save position: " + err.Error()
+	}
+}
+
+func (ui *tui) reply(item *tuiItem, body string) error {
+	replyTo := item.comment.ID
+	if item.general {
+		replyTo = 0
+	}
+	return postComment(ui.client, ui.feedback.Repo, ui.feedback.PRNumber, replyTo, body)
+}
+
+func (ui *tui) url(item *tuiItem) string {
+	if item.comment.DiscussionURL != "" {
+		return item.comment.DiscussionURL
+	}
+	return ui.feedback.URL
+}
+
+func (ui *tui) render() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = defaultWidth, 24
+	}
+
+	var out strings.Builder
+	out.WriteString("\033[H\033[2J")
+	fmt.Fprintf(&out, "%s%s #%d%s\n", colorBold, ui.feedback.Title, ui.feedback.PRNumber, colorReset)
+	fmt.Fprintf(&out, "%sj/k move • r reply • R resolve • o open • y copy link • q quit%s\n\n", colorGray, colorReset)
+
+	// Half the screen lists threads, the rest previews the highlighted one
+	listHeight := height/2 - 3
+	if listHeight < 3 {
+		listHeight = 3
+	}
+	if ui.cursor < ui.offset {
+		ui.offset = ui.cursor
+	} else if ui.cursor >= ui.offset+listHeight {
+		ui.offset = ui.cursor - listHeight + 1
+	}
+	for i := ui.offset; i < len(ui.items) && i < ui.offset+listHeight; i++ {
+		out.WriteString(ui.renderItem(i, width))
+		out.WriteString("\n")
+	}
+
+	fmt.Fprintf(&out, "\n%s\n", strings.Repeat("─", width))
+	preview := wrapBody(ui.items[ui.cursor].comment.Body, width)
+	for i, line := range preview {
+		if i >= height-listHeight-7 {
+			fmt.Fprintf(&out, "%s…%s\n", colorGray, colorReset)
+			break
+		}
+		out.WriteString(line + "\n")
+	}
+
+	// Status or reply prompt on the last line
+	fmt.Fprintf(&out, "\033[%d;1H", height)
+	if ui.replying {
+		fmt.Fprintf(&out, "%sReply:%s %s", colorCyan, colorReset, string(ui.input))
+	} else if ui.status != "" {
+		fmt.Fprintf(&out, "%s%s%s", colorYellow, ui.status, colorReset)
+	}
+
+	// Raw mode doesn't translate newlines into carriage returns
+	os.Stdout.WriteString(strings.ReplaceAll(out.String(), "\n", "\r\n"))
+}
+
+func (ui *tui) renderItem(i, width int) string {
+	item := ui.items[i]
+	comment := item.comment
+
+	marker := "  "
+	if i == ui.cursor {
+		marker = colorCyan + "▸ " + colorReset
+	}
+	symbol := colorYellow + "●" + colorReset
+	if item.resolved {
+		symbol = colorGreen + "✓" + colorReset
+	}
+
+	location := "general"
+	if comment.Path != "" {
+		location = comment.Path
+		if comment.Line != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+		}
+	}
+
+	extra := ""
+	if item.replies > 0 {
+		extra += fmt.Sprintf(" %s↩ %d%s", colorGray, item.replies, colorReset)
+	}
+	if item.pending != "" {
+		extra += fmt.Sprintf(" %s%s…%s", colorGray, item.pending, colorReset)
+	}
+
+	summary := firstLine(comment.Body)
+	room := width - runewidth.StringWidth(location) - runewidth.StringWidth(comment.Author) - 12
+	if room < 10 {
+		room = 10
+	}
+	summary = runewidth.Truncate(summary, room, "…")
+	return fmt.Sprintf("%s%s %s%s%s %s%s%s %s%s", marker, symbol, colorBlue, location, colorReset, colorBold+authorColor(comment.Author), comment.Author, colorReset, summary, extra)
+}
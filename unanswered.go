@@ -0,0 +1,41 @@
+package main
+
+import "strings"
+
+// filterUnanswered keeps only the feedback still waiting on a response from
+// the PR's author: threads whose latest message is from someone else, and
+// general comments the author hasn't posted after. It needs the replies, so
+// it runs before --no-replies drops them.
+func filterUnanswered(feedback *PRFeedback) {
+	isAuthor := func(comment ReviewComment) bool {
+		return strings.EqualFold(strings.TrimPrefix(comment.Author, "@"), feedback.Author)
+	}
+
+	kept := feedback.Comments[:0]
+	for _, comment := range feedback.Comments {
+		last := comment
+		if len(comment.Replies) > 0 {
+			last = comment.Replies[len(comment.Replies)-1]
+		}
+		if !isAuthor(last) {
+			kept = append(kept, comment)
+		}
+	}
+	feedback.Comments = kept
+
+	// General comments aren't threaded, so the author's latest one answers
+	// everything before it
+	answeredAt := ""
+	for _, comment := range feedback.GeneralIssues {
+		if isAuthor(comment) && comment.CreatedAt > answeredAt {
+			answeredAt = comment.CreatedAt
+		}
+	}
+	general := feedback.GeneralIssues[:0]
+	for _, comment := range feedback.GeneralIssues {
+		if !isAuthor(comment) && comment.CreatedAt > answeredAt {
+			general = append(general, comment)
+		}
+	}
+	feedback.GeneralIssues = general
+}
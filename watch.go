@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// watchInterval is how often --watch polls the PR
+const watchInterval = 30 * time.Second
+
+// watchEvent is one change to the PR's feedback between polls. With --json
+// they're written one per line (NDJSON), so bots can follow a PR's feedback
+// without setting up webhooks. The first poll reports everything as added.
+type watchEvent struct {
+	// Type is comment_added, comment_updated, comment_removed, check_failed
+	// or check_recovered
+	Type     string         `json:"type"`
+	At       string         `json:"at"`
+	Repo     string         `json:"repo"`
+	PRNumber int            `json:"pr_number"`
+	Comment  *ReviewComment `json:"comment,omitempty"`
+	Check    *StatusCheck   `json:"check,omitempty"`
+}
+
+// runWatch polls the PR until interrupted, reporting what changed since the
+// previous poll
+func runWatch(provider Provider, opts *options) {
+	var previous *PRFeedback
+	encoder := json.NewEncoder(os.Stdout)
+	if !opts.jsonOutput {
+		fmt.Fprintf(os.Stderr, "Watching PR #%d every %s, press Ctrl-C to stop\n", opts.prNumber, formatDuration(watchInterval))
+	}
+	for {
+		feedback, _, err := fetchFeedback(provider, opts)
+		if err != nil {
+			warnf("failed to fetch PR feedback: %v", err)
+		} else {
+			filterFeedback(feedback, opts)
+			for _, event := range diffFeedback(previous, feedback) {
+				if opts.jsonOutput {
+					encoder.Encode(event)
+				} else {
+					printWatchEvent(event)
+				}
+			}
+			previous = feedback
+		}
+		time.Sleep(watchInterval)
+	}
+}
+
+// diffFeedback returns the events that turn previous into current. Threads
+// count as updated when edited or replied to, and checks as failing again
+// when a rerun fails.
+func diffFeedback(previous, current *PRFeedback) []watchEvent {
+	at := now().UTC().Format(time.RFC3339)
+	event := func(kind string) watchEvent {
+		return watchEvent{Type: kind, At: at, Repo: current.Repo, PRNumber: current.PRNumber}
+	}
+	var events []watchEvent
+
+	comments := func(feedback *PRFeedback) map[int]ReviewComment {
+		byID := make(map[int]ReviewComment)
+		if feedback == nil {
+			return byID
+		}
+		for _, list := range [][]ReviewComment{feedback.GeneralIssues, feedback.Comments} {
+			for _, comment := range list {
+				byID[comment.ID] = comment
+			}
+		}
+		return byID
+	}
+	before, after := comments(previous), comments(current)
+	for _, list := range [][]ReviewComment{current.GeneralIssues, current.Comments} {
+		for _, comment := range list {
+			old, ok := before[comment.ID]
+			switch {
+			case !ok:
+				e := event("comment_added")
+				e.Comment = &comment
+				events = append(events, e)
+			case old.UpdatedAt != comment.UpdatedAt || old.ReplyCount != comment.ReplyCount:
+				e := event("comment_updated")
+				e.Comment = &comment
+				events = append(events, e)
+			}
+		}
+	}
+	for id, comment := range before {
+		if _, ok := after[id]; !ok {
+			e := event("comment_removed")
+			e.Comment = &comment
+			events = append(events, e)
+		}
+	}
+
+	type checkKey struct{ workflow, name string }
+	checks := func(feedback *PRFeedback) map[checkKey]StatusCheck {
+		byKey := make(map[checkKey]StatusCheck)
+		if feedback == nil {
+			return byKey
+		}
+		for _, check := range feedback.StatusChecks {
+			byKey[checkKey{check.WorkflowName, check.Name}] = check
+		}
+		return byKey
+	}
+	failedBefore, failedNow := checks(previous), checks(current)
+	for _, check := range current.StatusChecks {
+		old, ok := failedBefore[checkKey{check.WorkflowName, check.Name}]
+		if !ok || old.RunID != check.RunID {
+			e := event("check_failed")
+			e.Check = &check
+			events = append(events, e)
+		}
+	}
+	if previous != nil {
+		for _, check := range previous.StatusChecks {
+			if _, ok := failedNow[checkKey{check.WorkflowName, check.Name}]; !ok {
+				e := event("check_recovered")
+				e.Check = &check
+				events = append(events, e)
+			}
+		}
+	}
+	return events
+}
+
+// printWatchEvent prints an event as one line
+func printWatchEvent(event watchEvent) {
+	stamp := now().Format("15:04:05")
+	if comment := event.Comment; comment != nil {
+		location := "general comment"
+		if comment.Path != "" {
+			location = comment.Path
+			if line := commentLine(*comment); line > 0 {
+				location = fmt.Sprintf("%s:%d", comment.Path, line)
+			}
+		}
+		symbol, what := "+", "New"
+		switch event.Type {
+		case "comment_updated":
+			symbol, what = "~", "Updated"
+		case "comment_removed":
+			symbol, what = "✓", "Gone"
+		}
+		fmt.Printf("%s%s%s %s %s from @%s on %s: %s\n", colorGray, stamp, colorReset, symbol, what, comment.Author, location, firstLine(comment.Body))
+		return
+	}
+	if check := event.Check; check != nil {
+		if event.Type == "check_recovered" {
+			fmt.Printf("%s%s%s %s✓%s %s is no longer failing\n", colorGray, stamp, colorReset, colorGreen, colorReset, check.Name)
+		} else {
+			fmt.Printf("%s%s%s %s✗%s %s failed (%s)\n", colorGray, stamp, colorReset, colorRed, colorReset, check.Name, check.DetailsURL)
+		}
+	}
+}
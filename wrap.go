@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/mattn/go-runewidth"
+)
+
+// defaultWidth is used for separators when the output isn't a terminal
+const defaultWidth = 100
+
+// terminalWidth returns the usable width of stdout, or 0 when stdout isn't a
+// terminal and wrapping should be left to whatever consumes the output.
+func terminalWidth() int {
+	t := term.FromEnv()
+	if !t.IsTerminalOutput() {
+		return 0
+	}
+	width, _, err := t.Size()
+	if err != nil || width <= 0 {
+		return defaultWidth
+	}
+	return width
+}
+
+// separatorWidth is the width of the horizontal rules between sections
+func separatorWidth() int {
+	if width := terminalWidth(); width > 0 && width < defaultWidth {
+		return width
+	}
+	return defaultWidth
+}
+
+// wrapBody splits a comment body into display lines no wider than width
+// columns. Widths are measured in terminal cells, so CJK characters and emoji
+// count as two columns. Fenced code blocks are left untouched.
+func wrapBody(body string, width int) []string {
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			out = append(out, line)
+			continue
+		}
+		if inFence || width <= 0 {
+			out = append(out, line)
+			continue
+		}
+		out = append(out, wrapLine(line, width)...)
+	}
+	return out
+}
+
+// wrapLine word-wraps a single line, keeping its leading indentation on
+// continuation lines and hard-breaking words that are wider than the line.
+func wrapLine(line string, width int) []string {
+	if runewidth.StringWidth(line) <= width {
+		return []string{line}
+	}
+
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	indentWidth := runewidth.StringWidth(indent)
+	if indentWidth >= width/2 {
+		indent, indentWidth = "", 0
+	}
+
+	var lines []string
+	current := indent
+	currentWidth := indentWidth
+	for _, word := range strings.Fields(line) {
+		wordWidth := runewidth.StringWidth(word)
+
+		if currentWidth > indentWidth && currentWidth+1+wordWidth > width {
+			lines = append(lines, current)
+			current, currentWidth = indent, indentWidth
+		}
+
+		// Break words that can't fit on a line of their own
+		for indentWidth+wordWidth > width {
+			if currentWidth > indentWidth {
+				lines = append(lines, current)
+				current, currentWidth = indent, indentWidth
+			}
+			head := runewidth.Truncate(word, width-indentWidth, "")
+			if head == "" {
+				// A single wide rune is wider than the space left
+				head = string([]rune(word)[:1])
+			}
+			lines = append(lines, indent+head)
+			word = word[len(head):]
+			wordWidth = runewidth.StringWidth(word)
+		}
+		if word == "" {
+			continue
+		}
+
+		if currentWidth > indentWidth {
+			current += " "
+			currentWidth++
+		}
+		current += word
+		currentWidth += wordWidth
+	}
+	if currentWidth > indentWidth || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// hyperlink wraps text in an OSC 8 escape sequence so terminals that support
+// it make the text clickable. Output that isn't a terminal gets plain text.
+func hyperlink(url, text string) string {
+	if url == "" || !ansiEnabled || !term.FromEnv().IsTerminalOutput() {
+		return text
+	}
+	return "\033]8;;" + url + "\033\\" + text + "\033]8;;\033\\"
+}